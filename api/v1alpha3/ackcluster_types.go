@@ -38,7 +38,7 @@ type ACKClusterSpec struct {
 	CpuPolicy          string `json:"cpu_policy"`
 	MasterInstanceType string `json:"master_instance_type"`
 	NodesNum           int64  `json:"nodes_num"`
-	WorkerInstanceType string `json:"master_instance_type"`
+	WorkerInstanceType string `json:"worker_instance_type"`
 
 	// login
 	LoginSpec LoginSpec `json:"login_spec"`
@@ -57,7 +57,9 @@ type LoginSpec struct {
 type NetworkSpec struct {
 	// VPC ID，可空。如果不设置，系统会自动创建VPC，系统创建的VPC网段为192.168.0.0/16。
 	// 说明 VpcId 和 vswitchid 只能同时为空或者同时都设置对应的值。
-	VpcId            string   `json:"vpc_id""`
+	VpcId string `json:"vpc_id"`
+	// VPC网段，仅在系统自动创建VPC时使用，默认为192.168.0.0/16。
+	VpcCidr          string   `json:"vpc_cidr,omitempty"`
 	MasterVswitchIds []string `json:"master_vswitch_ids"`
 	WorkerVswitchds  []string `json:"worker_vswitchds"`
 
@@ -72,7 +74,7 @@ type NetworkSpec struct {
 
 type VolumeSpec struct {
 	MasterSystemDisk ClusterSystemDisk `json:"master_system_disk"`
-	WorkerSystemDisk ClusterSystemDisk `json:"master_system_disk"`
+	WorkerSystemDisk ClusterSystemDisk `json:"worker_system_disk"`
 	DataDisk         []ClusterDataDisk `json:"data_disk"`
 }
 
@@ -95,7 +97,6 @@ type ClusterDataDisk struct {
 	Encrypted *bool
 }
 
-//
 type Addons struct {
 	Name    string
 	Version string
@@ -121,6 +122,8 @@ type ACKClusterStatus struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ackclusters,scope=Namespaced
+// +kubebuilder:subresource:status
 
 // ACKCluster is the Schema for the ackclusters API
 type ACKCluster struct {
@@ -31,6 +31,9 @@ const MachineFinalizer = "ackmachine.infrastructure.cluster.x-k8s.io"
 type ACKMachineSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+	// ProviderID is the unique identifier as specified by the cloud provider.
+	ProviderID *string `json:"providerID,omitempty"`
+
 	ClusterId string `json:"cluster_id"`
 	RegionId  string `json:"region_id,omitempty"`
 	ZoneId    string `json:"zone_id"`
@@ -87,15 +90,22 @@ type MachineVolumeSpec struct {
 
 type UserData struct {
 	Encryped *bool  `json:"encryped"`
-	Datas    string `json:"datas""`
+	Datas    string `json:"datas"`
 }
 
 // ACKMachineStatus defines the observed state of ACKMachine
 type ACKMachineStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	MachineId      string                     `json:"machine_id"`
-	InstanceId     string                     `json:"instance_id"`
+
+	// Ready is true when the provider resource is ready.
+	Ready bool `json:"ready"`
+
+	MachineId  string `json:"machine_id"`
+	InstanceId string `json:"instance_id"`
+	// InstanceState is the state of the ECS instance for this machine.
+	InstanceState *InstanceState `json:"instanceState,omitempty"`
+
 	FailureReason  *errors.MachineStatusError `json:"failureReason,omitempty"`
 	FailureMessage *string                    `json:"failureMessage,omitempty"`
 }
@@ -105,6 +115,7 @@ type Tags struct {
 }
 
 // todo
+// +k8s:conversion-gen=false
 type NetworkInterface struct {
 	// 辅助弹性网卡名称
 	NetworkInterfaceName string
@@ -143,6 +154,8 @@ type DataDisk struct {
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ackmachines,scope=Namespaced
+// +kubebuilder:subresource:status
 
 // ACKMachine is the Schema for the ackmachines API
 type ACKMachine struct {
@@ -0,0 +1,232 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this ACKCluster to the Hub version (v1alpha4).
+func (src *ACKCluster) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha4.ACKCluster)
+	if err := Convert_v1alpha3_ACKCluster_To_v1alpha4_ACKCluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1alpha4.ACKCluster{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	dst.Status.MasterVSwitches = restored.Status.MasterVSwitches
+	dst.Status.WorkerVSwitches = restored.Status.WorkerVSwitches
+	dst.Status.FailureDomains = restored.Status.FailureDomains
+	dst.Status.FailureReason = restored.Status.FailureReason
+	dst.Status.FailureMessage = restored.Status.FailureMessage
+	dst.Spec.Tags = restored.Spec.Tags
+	dst.Spec.ResourceGroupId = restored.Spec.ResourceGroupId
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha4) to this version.
+func (dst *ACKCluster) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha4.ACKCluster)
+	if err := Convert_v1alpha4_ACKCluster_To_v1alpha3_ACKCluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
+}
+
+// ConvertTo converts this ACKClusterList to the Hub version (v1alpha4).
+func (src *ACKClusterList) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha4.ACKClusterList)
+	dst.ListMeta = src.ListMeta
+	if src.Items != nil {
+		dst.Items = make([]v1alpha4.ACKCluster, len(src.Items))
+		for i := range src.Items {
+			if err := src.Items[i].ConvertTo(&dst.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha4) to this version.
+func (dst *ACKClusterList) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha4.ACKClusterList)
+	dst.ListMeta = src.ListMeta
+	if src.Items != nil {
+		dst.Items = make([]ACKCluster, len(src.Items))
+		for i := range src.Items {
+			if err := dst.Items[i].ConvertFrom(&src.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ConvertTo converts this ACKMachine to the Hub version (v1alpha4).
+func (src *ACKMachine) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha4.ACKMachine)
+	if err := Convert_v1alpha3_ACKMachine_To_v1alpha4_ACKMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &v1alpha4.ACKMachine{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.LaunchTemplate = restored.Spec.LaunchTemplate
+	dst.Spec.EIP = restored.Spec.EIP
+	dst.Spec.NetworkInterfaces = restored.Spec.NetworkInterfaces
+	dst.Spec.SpotOptions = restored.Spec.SpotOptions
+	dst.Spec.InstanceChargeType = restored.Spec.InstanceChargeType
+	dst.Spec.Period = restored.Spec.Period
+	dst.Spec.PeriodUnit = restored.Spec.PeriodUnit
+	dst.Spec.InstanceTypes = restored.Spec.InstanceTypes
+	dst.Spec.ZoneIds = restored.Spec.ZoneIds
+	dst.Spec.ImageLookup = restored.Spec.ImageLookup
+	dst.Spec.ImageRef = restored.Spec.ImageRef
+	dst.Spec.Tags = restored.Spec.Tags
+	dst.Spec.ResourceGroupId = restored.Spec.ResourceGroupId
+	dst.Status.Addresses = restored.Status.Addresses
+	dst.Status.EIP = restored.Status.EIP
+	dst.Status.NetworkInterfaces = restored.Status.NetworkInterfaces
+	dst.Status.Volumes = restored.Status.Volumes
+	dst.Status.LaunchTemplate = restored.Status.LaunchTemplate
+	dst.Status.ExpiredTime = restored.Status.ExpiredTime
+	dst.Status.InstanceType = restored.Status.InstanceType
+	dst.Status.ZoneId = restored.Status.ZoneId
+	dst.Status.VSwitchId = restored.Status.VSwitchId
+	dst.Status.NoStockZones = restored.Status.NoStockZones
+	dst.Status.ImageId = restored.Status.ImageId
+	dst.Status.Conditions = restored.Status.Conditions
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha4) to this version.
+func (dst *ACKMachine) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha4.ACKMachine)
+	if err := Convert_v1alpha4_ACKMachine_To_v1alpha3_ACKMachine(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
+}
+
+// ConvertTo converts this ACKMachineList to the Hub version (v1alpha4).
+func (src *ACKMachineList) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha4.ACKMachineList)
+	dst.ListMeta = src.ListMeta
+	if src.Items != nil {
+		dst.Items = make([]v1alpha4.ACKMachine, len(src.Items))
+		for i := range src.Items {
+			if err := src.Items[i].ConvertTo(&dst.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha4) to this version.
+func (dst *ACKMachineList) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha4.ACKMachineList)
+	dst.ListMeta = src.ListMeta
+	if src.Items != nil {
+		dst.Items = make([]ACKMachine, len(src.Items))
+		for i := range src.Items {
+			if err := dst.Items[i].ConvertFrom(&src.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Convert_v1alpha4_ACKClusterSpec_To_v1alpha3_ACKClusterSpec drops the fields missing in v1alpha3,
+// ConvertTo restores them from the annotation.
+func Convert_v1alpha4_ACKClusterSpec_To_v1alpha3_ACKClusterSpec(in *v1alpha4.ACKClusterSpec, out *ACKClusterSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_ACKClusterSpec_To_v1alpha3_ACKClusterSpec(in, out, s)
+}
+
+// Convert_v1alpha4_ACKClusterStatus_To_v1alpha3_ACKClusterStatus drops the fields missing in v1alpha3,
+// ConvertTo restores them from the annotation.
+func Convert_v1alpha4_ACKClusterStatus_To_v1alpha3_ACKClusterStatus(in *v1alpha4.ACKClusterStatus, out *ACKClusterStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_ACKClusterStatus_To_v1alpha3_ACKClusterStatus(in, out, s)
+}
+
+// Convert_v1alpha4_ACKMachineSpec_To_v1alpha3_ACKMachineSpec drops the fields missing in v1alpha3,
+// ConvertTo restores them from the annotation.
+func Convert_v1alpha4_ACKMachineSpec_To_v1alpha3_ACKMachineSpec(in *v1alpha4.ACKMachineSpec, out *ACKMachineSpec, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_ACKMachineSpec_To_v1alpha3_ACKMachineSpec(in, out, s)
+}
+
+// Convert_v1alpha4_ACKMachineStatus_To_v1alpha3_ACKMachineStatus drops the fields missing in v1alpha3,
+// ConvertTo restores them from the annotation.
+func Convert_v1alpha4_ACKMachineStatus_To_v1alpha3_ACKMachineStatus(in *v1alpha4.ACKMachineStatus, out *ACKMachineStatus, s apiconversion.Scope) error {
+	return autoConvert_v1alpha4_ACKMachineStatus_To_v1alpha3_ACKMachineStatus(in, out, s)
+}
+
+// Convert_v1alpha3_NetworkSpec_To_v1alpha4_NetworkSpec converts the misspelled worker vswitch ids.
+func Convert_v1alpha3_NetworkSpec_To_v1alpha4_NetworkSpec(in *NetworkSpec, out *v1alpha4.NetworkSpec, s apiconversion.Scope) error {
+	if err := autoConvert_v1alpha3_NetworkSpec_To_v1alpha4_NetworkSpec(in, out, s); err != nil {
+		return err
+	}
+	out.WorkerVswitchIds = in.WorkerVswitchds
+	return nil
+}
+
+// Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec converts the misspelled worker vswitch ids.
+func Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in *v1alpha4.NetworkSpec, out *NetworkSpec, s apiconversion.Scope) error {
+	if err := autoConvert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in, out, s); err != nil {
+		return err
+	}
+	out.WorkerVswitchds = in.WorkerVswitchIds
+	return nil
+}
+
+// Convert_v1alpha3_Tags_To_v1alpha4_Tags converts the single tag to a map of tags.
+func Convert_v1alpha3_Tags_To_v1alpha4_Tags(in *Tags, out *v1alpha4.Tags, s apiconversion.Scope) error {
+	*out = nil
+	if in.Key != "" || in.Value != "" {
+		*out = v1alpha4.Tags{in.Key: in.Value}
+	}
+	return nil
+}
+
+// Convert_v1alpha4_Tags_To_v1alpha3_Tags converts the first of the sorted tags, the others
+// are restored from the annotation.
+func Convert_v1alpha4_Tags_To_v1alpha3_Tags(in *v1alpha4.Tags, out *Tags, s apiconversion.Scope) error {
+	*out = Tags{}
+	for _, key := range in.Keys() {
+		*out = Tags{Key: key, Value: (*in)[key]}
+		break
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+)
+
+func TestFuzzyConversion(t *testing.T) {
+	g := NewWithT(t)
+	scheme := runtime.NewScheme()
+	g.Expect(AddToScheme(scheme)).To(Succeed())
+	g.Expect(v1alpha4.AddToScheme(scheme)).To(Succeed())
+
+	t.Run("for ACKCluster", utilconversion.FuzzTestFunc(scheme, &v1alpha4.ACKCluster{}, &ACKCluster{}))
+	t.Run("for ACKMachine", utilconversion.FuzzTestFunc(scheme, &v1alpha4.ACKMachine{}, &ACKMachine{}))
+}
+
+func TestStoredFieldNames(t *testing.T) {
+	g := NewWithT(t)
+
+	// v1alpha3 objects stored before v1alpha4 use the untagged Go field names
+	cluster := &ACKCluster{}
+	g.Expect(json.Unmarshal([]byte(`{"spec":{"networkSpec":{"EndpointPublicAccess":true},"addons":{"Name":"flannel"},"volume_spec":{"data_disk":[{"Size":40}]}}}`), cluster)).To(Succeed())
+	g.Expect(cluster.Spec.NetworkSpec.EndpointPublicAccess).NotTo(BeNil())
+	g.Expect(*cluster.Spec.NetworkSpec.EndpointPublicAccess).To(BeTrue())
+	g.Expect(cluster.Spec.Addons.Name).To(Equal("flannel"))
+	g.Expect(cluster.Spec.VolumeSpec.DataDisk).To(HaveLen(1))
+	g.Expect(cluster.Spec.VolumeSpec.DataDisk[0].Size).To(BeEquivalentTo(40))
+
+	machine := &ACKMachine{}
+	g.Expect(json.Unmarshal([]byte(`{"spec":{"machine_volume_spec":{"system_disk":{"PerformanceLevel":"PL1","AutoSnapshotPolicyId":"sp-1"}}}}`), machine)).To(Succeed())
+	g.Expect(machine.Spec.MachineVolumeSpec.SystemDisk.PerformanceLevel).To(Equal("PL1"))
+	g.Expect(machine.Spec.MachineVolumeSpec.SystemDisk.AutoSnapshotPolicyId).To(Equal("sp-1"))
+}
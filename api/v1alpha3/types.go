@@ -1,10 +1,11 @@
 package v1alpha3
 
+// +k8s:conversion-gen=false
 type Instance struct {
 	Id           string `json:"id,omitempty"`
 	InstanceName string `json:"instance_name"`
 
-	State string `json:"state"`
+	State InstanceState `json:"state"`
 
 	RegionId string `json:"region_id"`
 	ZoneId   string `json:"zone_id"`
@@ -68,8 +69,26 @@ type VpcAttributes struct {
 	VpcId string `json:"vpc_id"`
 }
 
-type InstanceState struct {
-}
+// InstanceState describes the state of an ECS instance.
+type InstanceState string
+
+var (
+	// InstanceStatePending is the string representing an instance in a pending state
+	InstanceStatePending = InstanceState("Pending")
+
+	// InstanceStateStarting is the string representing an instance in a starting state
+	InstanceStateStarting = InstanceState("Starting")
+
+	// InstanceStateRunning is the string representing an instance in a running state
+	InstanceStateRunning = InstanceState("Running")
+
+	// InstanceStateStopping is the string representing an instance in a stopping state
+	InstanceStateStopping = InstanceState("Stopping")
+
+	// InstanceStateStopped is the string representing an instance in a stopped state
+	InstanceStateStopped = InstanceState("Stopped")
+)
 
+// +k8s:conversion-gen=false
 type EipAddress struct {
 }
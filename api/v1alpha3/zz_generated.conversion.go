@@ -0,0 +1,967 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v1alpha3
+
+import (
+	unsafe "unsafe"
+
+	v1alpha4 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	errors "sigs.k8s.io/cluster-api/errors"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*ACKCluster)(nil), (*v1alpha4.ACKCluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ACKCluster_To_v1alpha4_ACKCluster(a.(*ACKCluster), b.(*v1alpha4.ACKCluster), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.ACKCluster)(nil), (*ACKCluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ACKCluster_To_v1alpha3_ACKCluster(a.(*v1alpha4.ACKCluster), b.(*ACKCluster), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ACKClusterList)(nil), (*v1alpha4.ACKClusterList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ACKClusterList_To_v1alpha4_ACKClusterList(a.(*ACKClusterList), b.(*v1alpha4.ACKClusterList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.ACKClusterList)(nil), (*ACKClusterList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ACKClusterList_To_v1alpha3_ACKClusterList(a.(*v1alpha4.ACKClusterList), b.(*ACKClusterList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ACKClusterSpec)(nil), (*v1alpha4.ACKClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ACKClusterSpec_To_v1alpha4_ACKClusterSpec(a.(*ACKClusterSpec), b.(*v1alpha4.ACKClusterSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ACKClusterStatus)(nil), (*v1alpha4.ACKClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ACKClusterStatus_To_v1alpha4_ACKClusterStatus(a.(*ACKClusterStatus), b.(*v1alpha4.ACKClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ACKMachine)(nil), (*v1alpha4.ACKMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ACKMachine_To_v1alpha4_ACKMachine(a.(*ACKMachine), b.(*v1alpha4.ACKMachine), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.ACKMachine)(nil), (*ACKMachine)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ACKMachine_To_v1alpha3_ACKMachine(a.(*v1alpha4.ACKMachine), b.(*ACKMachine), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ACKMachineList)(nil), (*v1alpha4.ACKMachineList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ACKMachineList_To_v1alpha4_ACKMachineList(a.(*ACKMachineList), b.(*v1alpha4.ACKMachineList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.ACKMachineList)(nil), (*ACKMachineList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ACKMachineList_To_v1alpha3_ACKMachineList(a.(*v1alpha4.ACKMachineList), b.(*ACKMachineList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ACKMachineSpec)(nil), (*v1alpha4.ACKMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ACKMachineSpec_To_v1alpha4_ACKMachineSpec(a.(*ACKMachineSpec), b.(*v1alpha4.ACKMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ACKMachineStatus)(nil), (*v1alpha4.ACKMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ACKMachineStatus_To_v1alpha4_ACKMachineStatus(a.(*ACKMachineStatus), b.(*v1alpha4.ACKMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Addons)(nil), (*v1alpha4.Addons)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_Addons_To_v1alpha4_Addons(a.(*Addons), b.(*v1alpha4.Addons), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.Addons)(nil), (*Addons)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_Addons_To_v1alpha3_Addons(a.(*v1alpha4.Addons), b.(*Addons), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ClusterDataDisk)(nil), (*v1alpha4.ClusterDataDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ClusterDataDisk_To_v1alpha4_ClusterDataDisk(a.(*ClusterDataDisk), b.(*v1alpha4.ClusterDataDisk), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.ClusterDataDisk)(nil), (*ClusterDataDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ClusterDataDisk_To_v1alpha3_ClusterDataDisk(a.(*v1alpha4.ClusterDataDisk), b.(*ClusterDataDisk), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ClusterSystemDisk)(nil), (*v1alpha4.ClusterSystemDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ClusterSystemDisk_To_v1alpha4_ClusterSystemDisk(a.(*ClusterSystemDisk), b.(*v1alpha4.ClusterSystemDisk), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.ClusterSystemDisk)(nil), (*ClusterSystemDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ClusterSystemDisk_To_v1alpha3_ClusterSystemDisk(a.(*v1alpha4.ClusterSystemDisk), b.(*ClusterSystemDisk), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CpuOptions)(nil), (*v1alpha4.CpuOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_CpuOptions_To_v1alpha4_CpuOptions(a.(*CpuOptions), b.(*v1alpha4.CpuOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.CpuOptions)(nil), (*CpuOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_CpuOptions_To_v1alpha3_CpuOptions(a.(*v1alpha4.CpuOptions), b.(*CpuOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DataDisk)(nil), (*v1alpha4.DataDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_DataDisk_To_v1alpha4_DataDisk(a.(*DataDisk), b.(*v1alpha4.DataDisk), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.DataDisk)(nil), (*DataDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_DataDisk_To_v1alpha3_DataDisk(a.(*v1alpha4.DataDisk), b.(*DataDisk), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoginSpec)(nil), (*v1alpha4.LoginSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_LoginSpec_To_v1alpha4_LoginSpec(a.(*LoginSpec), b.(*v1alpha4.LoginSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.LoginSpec)(nil), (*LoginSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_LoginSpec_To_v1alpha3_LoginSpec(a.(*v1alpha4.LoginSpec), b.(*LoginSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MachineNetworkSpec)(nil), (*v1alpha4.MachineNetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_MachineNetworkSpec_To_v1alpha4_MachineNetworkSpec(a.(*MachineNetworkSpec), b.(*v1alpha4.MachineNetworkSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.MachineNetworkSpec)(nil), (*MachineNetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_MachineNetworkSpec_To_v1alpha3_MachineNetworkSpec(a.(*v1alpha4.MachineNetworkSpec), b.(*MachineNetworkSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MachineVolumeSpec)(nil), (*v1alpha4.MachineVolumeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_MachineVolumeSpec_To_v1alpha4_MachineVolumeSpec(a.(*MachineVolumeSpec), b.(*v1alpha4.MachineVolumeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.MachineVolumeSpec)(nil), (*MachineVolumeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_MachineVolumeSpec_To_v1alpha3_MachineVolumeSpec(a.(*v1alpha4.MachineVolumeSpec), b.(*MachineVolumeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SystemDisk)(nil), (*v1alpha4.SystemDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_SystemDisk_To_v1alpha4_SystemDisk(a.(*SystemDisk), b.(*v1alpha4.SystemDisk), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.SystemDisk)(nil), (*SystemDisk)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_SystemDisk_To_v1alpha3_SystemDisk(a.(*v1alpha4.SystemDisk), b.(*SystemDisk), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*UserData)(nil), (*v1alpha4.UserData)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_UserData_To_v1alpha4_UserData(a.(*UserData), b.(*v1alpha4.UserData), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.UserData)(nil), (*UserData)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_UserData_To_v1alpha3_UserData(a.(*v1alpha4.UserData), b.(*UserData), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VolumeSpec)(nil), (*v1alpha4.VolumeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_VolumeSpec_To_v1alpha4_VolumeSpec(a.(*VolumeSpec), b.(*v1alpha4.VolumeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.VolumeSpec)(nil), (*VolumeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_VolumeSpec_To_v1alpha3_VolumeSpec(a.(*v1alpha4.VolumeSpec), b.(*VolumeSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*VpcAttributes)(nil), (*v1alpha4.VpcAttributes)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_VpcAttributes_To_v1alpha4_VpcAttributes(a.(*VpcAttributes), b.(*v1alpha4.VpcAttributes), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha4.VpcAttributes)(nil), (*VpcAttributes)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_VpcAttributes_To_v1alpha3_VpcAttributes(a.(*v1alpha4.VpcAttributes), b.(*VpcAttributes), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*NetworkSpec)(nil), (*v1alpha4.NetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_NetworkSpec_To_v1alpha4_NetworkSpec(a.(*NetworkSpec), b.(*v1alpha4.NetworkSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*Tags)(nil), (*v1alpha4.Tags)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_Tags_To_v1alpha4_Tags(a.(*Tags), b.(*v1alpha4.Tags), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.ACKClusterSpec)(nil), (*ACKClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ACKClusterSpec_To_v1alpha3_ACKClusterSpec(a.(*v1alpha4.ACKClusterSpec), b.(*ACKClusterSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.ACKClusterStatus)(nil), (*ACKClusterStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ACKClusterStatus_To_v1alpha3_ACKClusterStatus(a.(*v1alpha4.ACKClusterStatus), b.(*ACKClusterStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.ACKMachineSpec)(nil), (*ACKMachineSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ACKMachineSpec_To_v1alpha3_ACKMachineSpec(a.(*v1alpha4.ACKMachineSpec), b.(*ACKMachineSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.ACKMachineStatus)(nil), (*ACKMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_ACKMachineStatus_To_v1alpha3_ACKMachineStatus(a.(*v1alpha4.ACKMachineStatus), b.(*ACKMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.NetworkSpec)(nil), (*NetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(a.(*v1alpha4.NetworkSpec), b.(*NetworkSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1alpha4.Tags)(nil), (*Tags)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha4_Tags_To_v1alpha3_Tags(a.(*v1alpha4.Tags), b.(*Tags), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha3_ACKCluster_To_v1alpha4_ACKCluster(in *ACKCluster, out *v1alpha4.ACKCluster, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha3_ACKClusterSpec_To_v1alpha4_ACKClusterSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_ACKClusterStatus_To_v1alpha4_ACKClusterStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha3_ACKCluster_To_v1alpha4_ACKCluster is an autogenerated conversion function.
+func Convert_v1alpha3_ACKCluster_To_v1alpha4_ACKCluster(in *ACKCluster, out *v1alpha4.ACKCluster, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ACKCluster_To_v1alpha4_ACKCluster(in, out, s)
+}
+
+func autoConvert_v1alpha4_ACKCluster_To_v1alpha3_ACKCluster(in *v1alpha4.ACKCluster, out *ACKCluster, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha4_ACKClusterSpec_To_v1alpha3_ACKClusterSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_ACKClusterStatus_To_v1alpha3_ACKClusterStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha4_ACKCluster_To_v1alpha3_ACKCluster is an autogenerated conversion function.
+func Convert_v1alpha4_ACKCluster_To_v1alpha3_ACKCluster(in *v1alpha4.ACKCluster, out *ACKCluster, s conversion.Scope) error {
+	return autoConvert_v1alpha4_ACKCluster_To_v1alpha3_ACKCluster(in, out, s)
+}
+
+func autoConvert_v1alpha3_ACKClusterList_To_v1alpha4_ACKClusterList(in *ACKClusterList, out *v1alpha4.ACKClusterList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha4.ACKCluster, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_ACKCluster_To_v1alpha4_ACKCluster(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha3_ACKClusterList_To_v1alpha4_ACKClusterList is an autogenerated conversion function.
+func Convert_v1alpha3_ACKClusterList_To_v1alpha4_ACKClusterList(in *ACKClusterList, out *v1alpha4.ACKClusterList, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ACKClusterList_To_v1alpha4_ACKClusterList(in, out, s)
+}
+
+func autoConvert_v1alpha4_ACKClusterList_To_v1alpha3_ACKClusterList(in *v1alpha4.ACKClusterList, out *ACKClusterList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ACKCluster, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_ACKCluster_To_v1alpha3_ACKCluster(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha4_ACKClusterList_To_v1alpha3_ACKClusterList is an autogenerated conversion function.
+func Convert_v1alpha4_ACKClusterList_To_v1alpha3_ACKClusterList(in *v1alpha4.ACKClusterList, out *ACKClusterList, s conversion.Scope) error {
+	return autoConvert_v1alpha4_ACKClusterList_To_v1alpha3_ACKClusterList(in, out, s)
+}
+
+func autoConvert_v1alpha3_ACKClusterSpec_To_v1alpha4_ACKClusterSpec(in *ACKClusterSpec, out *v1alpha4.ACKClusterSpec, s conversion.Scope) error {
+	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	out.ClusterName = in.ClusterName
+	out.ClusterType = in.ClusterType
+	out.RegionId = in.RegionId
+	out.KubernetesVersion = in.KubernetesVersion
+	out.CpuPolicy = in.CpuPolicy
+	out.MasterInstanceType = in.MasterInstanceType
+	out.NodesNum = in.NodesNum
+	out.WorkerInstanceType = in.WorkerInstanceType
+	if err := Convert_v1alpha3_LoginSpec_To_v1alpha4_LoginSpec(&in.LoginSpec, &out.LoginSpec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_VolumeSpec_To_v1alpha4_VolumeSpec(&in.VolumeSpec, &out.VolumeSpec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_NetworkSpec_To_v1alpha4_NetworkSpec(&in.NetworkSpec, &out.NetworkSpec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_Addons_To_v1alpha4_Addons(&in.Addons, &out.Addons, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_Tags_To_v1alpha4_Tags(&in.Tags, &out.Tags, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha3_ACKClusterSpec_To_v1alpha4_ACKClusterSpec is an autogenerated conversion function.
+func Convert_v1alpha3_ACKClusterSpec_To_v1alpha4_ACKClusterSpec(in *ACKClusterSpec, out *v1alpha4.ACKClusterSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ACKClusterSpec_To_v1alpha4_ACKClusterSpec(in, out, s)
+}
+
+func autoConvert_v1alpha4_ACKClusterSpec_To_v1alpha3_ACKClusterSpec(in *v1alpha4.ACKClusterSpec, out *ACKClusterSpec, s conversion.Scope) error {
+	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	out.ClusterName = in.ClusterName
+	out.ClusterType = in.ClusterType
+	out.RegionId = in.RegionId
+	// WARNING: in.ResourceGroupId requires manual conversion: does not exist in peer-type
+	out.KubernetesVersion = in.KubernetesVersion
+	out.CpuPolicy = in.CpuPolicy
+	out.MasterInstanceType = in.MasterInstanceType
+	out.NodesNum = in.NodesNum
+	out.WorkerInstanceType = in.WorkerInstanceType
+	if err := Convert_v1alpha4_LoginSpec_To_v1alpha3_LoginSpec(&in.LoginSpec, &out.LoginSpec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_VolumeSpec_To_v1alpha3_VolumeSpec(&in.VolumeSpec, &out.VolumeSpec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(&in.NetworkSpec, &out.NetworkSpec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_Addons_To_v1alpha3_Addons(&in.Addons, &out.Addons, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_Tags_To_v1alpha3_Tags(&in.Tags, &out.Tags, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha3_ACKClusterStatus_To_v1alpha4_ACKClusterStatus(in *ACKClusterStatus, out *v1alpha4.ACKClusterStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.MasterInstanceIDs = *(*[]string)(unsafe.Pointer(&in.MasterInstanceIDs))
+	out.NodeInstanceIDs = *(*[]string)(unsafe.Pointer(&in.NodeInstanceIDs))
+	out.ScalingGroupID = in.ScalingGroupID
+	out.VpcId = in.VpcId
+	out.VSwitchIds = in.VSwitchIds
+	out.IntranetSlbId = in.IntranetSlbId
+	return nil
+}
+
+// Convert_v1alpha3_ACKClusterStatus_To_v1alpha4_ACKClusterStatus is an autogenerated conversion function.
+func Convert_v1alpha3_ACKClusterStatus_To_v1alpha4_ACKClusterStatus(in *ACKClusterStatus, out *v1alpha4.ACKClusterStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ACKClusterStatus_To_v1alpha4_ACKClusterStatus(in, out, s)
+}
+
+func autoConvert_v1alpha4_ACKClusterStatus_To_v1alpha3_ACKClusterStatus(in *v1alpha4.ACKClusterStatus, out *ACKClusterStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.MasterInstanceIDs = *(*[]string)(unsafe.Pointer(&in.MasterInstanceIDs))
+	out.NodeInstanceIDs = *(*[]string)(unsafe.Pointer(&in.NodeInstanceIDs))
+	out.ScalingGroupID = in.ScalingGroupID
+	out.VpcId = in.VpcId
+	out.VSwitchIds = in.VSwitchIds
+	out.IntranetSlbId = in.IntranetSlbId
+	// WARNING: in.MasterVSwitches requires manual conversion: does not exist in peer-type
+	// WARNING: in.WorkerVSwitches requires manual conversion: does not exist in peer-type
+	// WARNING: in.FailureDomains requires manual conversion: does not exist in peer-type
+	// WARNING: in.FailureReason requires manual conversion: does not exist in peer-type
+	// WARNING: in.FailureMessage requires manual conversion: does not exist in peer-type
+	return nil
+}
+
+func autoConvert_v1alpha3_ACKMachine_To_v1alpha4_ACKMachine(in *ACKMachine, out *v1alpha4.ACKMachine, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha3_ACKMachineSpec_To_v1alpha4_ACKMachineSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_ACKMachineStatus_To_v1alpha4_ACKMachineStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha3_ACKMachine_To_v1alpha4_ACKMachine is an autogenerated conversion function.
+func Convert_v1alpha3_ACKMachine_To_v1alpha4_ACKMachine(in *ACKMachine, out *v1alpha4.ACKMachine, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ACKMachine_To_v1alpha4_ACKMachine(in, out, s)
+}
+
+func autoConvert_v1alpha4_ACKMachine_To_v1alpha3_ACKMachine(in *v1alpha4.ACKMachine, out *ACKMachine, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha4_ACKMachineSpec_To_v1alpha3_ACKMachineSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_ACKMachineStatus_To_v1alpha3_ACKMachineStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha4_ACKMachine_To_v1alpha3_ACKMachine is an autogenerated conversion function.
+func Convert_v1alpha4_ACKMachine_To_v1alpha3_ACKMachine(in *v1alpha4.ACKMachine, out *ACKMachine, s conversion.Scope) error {
+	return autoConvert_v1alpha4_ACKMachine_To_v1alpha3_ACKMachine(in, out, s)
+}
+
+func autoConvert_v1alpha3_ACKMachineList_To_v1alpha4_ACKMachineList(in *ACKMachineList, out *v1alpha4.ACKMachineList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha4.ACKMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_ACKMachine_To_v1alpha4_ACKMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha3_ACKMachineList_To_v1alpha4_ACKMachineList is an autogenerated conversion function.
+func Convert_v1alpha3_ACKMachineList_To_v1alpha4_ACKMachineList(in *ACKMachineList, out *v1alpha4.ACKMachineList, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ACKMachineList_To_v1alpha4_ACKMachineList(in, out, s)
+}
+
+func autoConvert_v1alpha4_ACKMachineList_To_v1alpha3_ACKMachineList(in *v1alpha4.ACKMachineList, out *ACKMachineList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ACKMachine, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha4_ACKMachine_To_v1alpha3_ACKMachine(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha4_ACKMachineList_To_v1alpha3_ACKMachineList is an autogenerated conversion function.
+func Convert_v1alpha4_ACKMachineList_To_v1alpha3_ACKMachineList(in *v1alpha4.ACKMachineList, out *ACKMachineList, s conversion.Scope) error {
+	return autoConvert_v1alpha4_ACKMachineList_To_v1alpha3_ACKMachineList(in, out, s)
+}
+
+func autoConvert_v1alpha3_ACKMachineSpec_To_v1alpha4_ACKMachineSpec(in *ACKMachineSpec, out *v1alpha4.ACKMachineSpec, s conversion.Scope) error {
+	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
+	out.ClusterId = in.ClusterId
+	out.RegionId = in.RegionId
+	out.ZoneId = in.ZoneId
+	out.InstanceType = in.InstanceType
+	out.InstanceName = in.InstanceName
+	out.Description = in.Description
+	out.IoOptimized = in.IoOptimized
+	out.ImageId = in.ImageId
+	if err := Convert_v1alpha3_Tags_To_v1alpha4_Tags(&in.Tags, &out.Tags, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_UserData_To_v1alpha4_UserData(&in.UserData, &out.UserData, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_MachineNetworkSpec_To_v1alpha4_MachineNetworkSpec(&in.MachineNetworkSpec, &out.MachineNetworkSpec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_MachineVolumeSpec_To_v1alpha4_MachineVolumeSpec(&in.MachineVolumeSpec, &out.MachineVolumeSpec, s); err != nil {
+		return err
+	}
+	out.AutoRenew = in.AutoRenew
+	out.AutoRenewPeriod = in.AutoRenewPeriod
+	return nil
+}
+
+// Convert_v1alpha3_ACKMachineSpec_To_v1alpha4_ACKMachineSpec is an autogenerated conversion function.
+func Convert_v1alpha3_ACKMachineSpec_To_v1alpha4_ACKMachineSpec(in *ACKMachineSpec, out *v1alpha4.ACKMachineSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ACKMachineSpec_To_v1alpha4_ACKMachineSpec(in, out, s)
+}
+
+func autoConvert_v1alpha4_ACKMachineSpec_To_v1alpha3_ACKMachineSpec(in *v1alpha4.ACKMachineSpec, out *ACKMachineSpec, s conversion.Scope) error {
+	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
+	out.ClusterId = in.ClusterId
+	out.RegionId = in.RegionId
+	out.ZoneId = in.ZoneId
+	// WARNING: in.ZoneIds requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceGroupId requires manual conversion: does not exist in peer-type
+	// WARNING: in.LaunchTemplate requires manual conversion: does not exist in peer-type
+	out.InstanceType = in.InstanceType
+	// WARNING: in.InstanceTypes requires manual conversion: does not exist in peer-type
+	out.InstanceName = in.InstanceName
+	out.Description = in.Description
+	out.IoOptimized = in.IoOptimized
+	out.ImageId = in.ImageId
+	// WARNING: in.ImageLookup requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageRef requires manual conversion: does not exist in peer-type
+	if err := Convert_v1alpha4_Tags_To_v1alpha3_Tags(&in.Tags, &out.Tags, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_UserData_To_v1alpha3_UserData(&in.UserData, &out.UserData, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_MachineNetworkSpec_To_v1alpha3_MachineNetworkSpec(&in.MachineNetworkSpec, &out.MachineNetworkSpec, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_MachineVolumeSpec_To_v1alpha3_MachineVolumeSpec(&in.MachineVolumeSpec, &out.MachineVolumeSpec, s); err != nil {
+		return err
+	}
+	// WARNING: in.NetworkInterfaces requires manual conversion: does not exist in peer-type
+	// WARNING: in.EIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.SpotOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceChargeType requires manual conversion: does not exist in peer-type
+	// WARNING: in.Period requires manual conversion: does not exist in peer-type
+	// WARNING: in.PeriodUnit requires manual conversion: does not exist in peer-type
+	out.AutoRenew = in.AutoRenew
+	out.AutoRenewPeriod = in.AutoRenewPeriod
+	return nil
+}
+
+func autoConvert_v1alpha3_ACKMachineStatus_To_v1alpha4_ACKMachineStatus(in *ACKMachineStatus, out *v1alpha4.ACKMachineStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.MachineId = in.MachineId
+	out.InstanceId = in.InstanceId
+	out.InstanceState = (*v1alpha4.InstanceState)(unsafe.Pointer(in.InstanceState))
+	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
+	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	return nil
+}
+
+// Convert_v1alpha3_ACKMachineStatus_To_v1alpha4_ACKMachineStatus is an autogenerated conversion function.
+func Convert_v1alpha3_ACKMachineStatus_To_v1alpha4_ACKMachineStatus(in *ACKMachineStatus, out *v1alpha4.ACKMachineStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ACKMachineStatus_To_v1alpha4_ACKMachineStatus(in, out, s)
+}
+
+func autoConvert_v1alpha4_ACKMachineStatus_To_v1alpha3_ACKMachineStatus(in *v1alpha4.ACKMachineStatus, out *ACKMachineStatus, s conversion.Scope) error {
+	out.Ready = in.Ready
+	out.MachineId = in.MachineId
+	out.InstanceId = in.InstanceId
+	out.InstanceState = (*InstanceState)(unsafe.Pointer(in.InstanceState))
+	// WARNING: in.ImageId requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstanceType requires manual conversion: does not exist in peer-type
+	// WARNING: in.ZoneId requires manual conversion: does not exist in peer-type
+	// WARNING: in.VSwitchId requires manual conversion: does not exist in peer-type
+	// WARNING: in.NoStockZones requires manual conversion: does not exist in peer-type
+	// WARNING: in.Addresses requires manual conversion: does not exist in peer-type
+	// WARNING: in.EIP requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkInterfaces requires manual conversion: does not exist in peer-type
+	// WARNING: in.Volumes requires manual conversion: does not exist in peer-type
+	// WARNING: in.LaunchTemplate requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExpiredTime requires manual conversion: does not exist in peer-type
+	out.FailureReason = (*errors.MachineStatusError)(unsafe.Pointer(in.FailureReason))
+	out.FailureMessage = (*string)(unsafe.Pointer(in.FailureMessage))
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	return nil
+}
+
+func autoConvert_v1alpha3_Addons_To_v1alpha4_Addons(in *Addons, out *v1alpha4.Addons, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Version = in.Version
+	out.Config = in.Config
+	return nil
+}
+
+// Convert_v1alpha3_Addons_To_v1alpha4_Addons is an autogenerated conversion function.
+func Convert_v1alpha3_Addons_To_v1alpha4_Addons(in *Addons, out *v1alpha4.Addons, s conversion.Scope) error {
+	return autoConvert_v1alpha3_Addons_To_v1alpha4_Addons(in, out, s)
+}
+
+func autoConvert_v1alpha4_Addons_To_v1alpha3_Addons(in *v1alpha4.Addons, out *Addons, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Version = in.Version
+	out.Config = in.Config
+	return nil
+}
+
+// Convert_v1alpha4_Addons_To_v1alpha3_Addons is an autogenerated conversion function.
+func Convert_v1alpha4_Addons_To_v1alpha3_Addons(in *v1alpha4.Addons, out *Addons, s conversion.Scope) error {
+	return autoConvert_v1alpha4_Addons_To_v1alpha3_Addons(in, out, s)
+}
+
+func autoConvert_v1alpha3_ClusterDataDisk_To_v1alpha4_ClusterDataDisk(in *ClusterDataDisk, out *v1alpha4.ClusterDataDisk, s conversion.Scope) error {
+	out.Category = in.Category
+	out.Size = in.Size
+	out.Encrypted = (*bool)(unsafe.Pointer(in.Encrypted))
+	return nil
+}
+
+// Convert_v1alpha3_ClusterDataDisk_To_v1alpha4_ClusterDataDisk is an autogenerated conversion function.
+func Convert_v1alpha3_ClusterDataDisk_To_v1alpha4_ClusterDataDisk(in *ClusterDataDisk, out *v1alpha4.ClusterDataDisk, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ClusterDataDisk_To_v1alpha4_ClusterDataDisk(in, out, s)
+}
+
+func autoConvert_v1alpha4_ClusterDataDisk_To_v1alpha3_ClusterDataDisk(in *v1alpha4.ClusterDataDisk, out *ClusterDataDisk, s conversion.Scope) error {
+	out.Category = in.Category
+	out.Size = in.Size
+	out.Encrypted = (*bool)(unsafe.Pointer(in.Encrypted))
+	return nil
+}
+
+// Convert_v1alpha4_ClusterDataDisk_To_v1alpha3_ClusterDataDisk is an autogenerated conversion function.
+func Convert_v1alpha4_ClusterDataDisk_To_v1alpha3_ClusterDataDisk(in *v1alpha4.ClusterDataDisk, out *ClusterDataDisk, s conversion.Scope) error {
+	return autoConvert_v1alpha4_ClusterDataDisk_To_v1alpha3_ClusterDataDisk(in, out, s)
+}
+
+func autoConvert_v1alpha3_ClusterSystemDisk_To_v1alpha4_ClusterSystemDisk(in *ClusterSystemDisk, out *v1alpha4.ClusterSystemDisk, s conversion.Scope) error {
+	out.SystemDiskCategory = in.SystemDiskCategory
+	out.SystemDiskSize = in.SystemDiskSize
+	return nil
+}
+
+// Convert_v1alpha3_ClusterSystemDisk_To_v1alpha4_ClusterSystemDisk is an autogenerated conversion function.
+func Convert_v1alpha3_ClusterSystemDisk_To_v1alpha4_ClusterSystemDisk(in *ClusterSystemDisk, out *v1alpha4.ClusterSystemDisk, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ClusterSystemDisk_To_v1alpha4_ClusterSystemDisk(in, out, s)
+}
+
+func autoConvert_v1alpha4_ClusterSystemDisk_To_v1alpha3_ClusterSystemDisk(in *v1alpha4.ClusterSystemDisk, out *ClusterSystemDisk, s conversion.Scope) error {
+	out.SystemDiskCategory = in.SystemDiskCategory
+	out.SystemDiskSize = in.SystemDiskSize
+	return nil
+}
+
+// Convert_v1alpha4_ClusterSystemDisk_To_v1alpha3_ClusterSystemDisk is an autogenerated conversion function.
+func Convert_v1alpha4_ClusterSystemDisk_To_v1alpha3_ClusterSystemDisk(in *v1alpha4.ClusterSystemDisk, out *ClusterSystemDisk, s conversion.Scope) error {
+	return autoConvert_v1alpha4_ClusterSystemDisk_To_v1alpha3_ClusterSystemDisk(in, out, s)
+}
+
+func autoConvert_v1alpha3_CpuOptions_To_v1alpha4_CpuOptions(in *CpuOptions, out *v1alpha4.CpuOptions, s conversion.Scope) error {
+	out.Core = in.Core
+	out.ThreadsPerCore = in.ThreadsPerCore
+	return nil
+}
+
+// Convert_v1alpha3_CpuOptions_To_v1alpha4_CpuOptions is an autogenerated conversion function.
+func Convert_v1alpha3_CpuOptions_To_v1alpha4_CpuOptions(in *CpuOptions, out *v1alpha4.CpuOptions, s conversion.Scope) error {
+	return autoConvert_v1alpha3_CpuOptions_To_v1alpha4_CpuOptions(in, out, s)
+}
+
+func autoConvert_v1alpha4_CpuOptions_To_v1alpha3_CpuOptions(in *v1alpha4.CpuOptions, out *CpuOptions, s conversion.Scope) error {
+	out.Core = in.Core
+	out.ThreadsPerCore = in.ThreadsPerCore
+	return nil
+}
+
+// Convert_v1alpha4_CpuOptions_To_v1alpha3_CpuOptions is an autogenerated conversion function.
+func Convert_v1alpha4_CpuOptions_To_v1alpha3_CpuOptions(in *v1alpha4.CpuOptions, out *CpuOptions, s conversion.Scope) error {
+	return autoConvert_v1alpha4_CpuOptions_To_v1alpha3_CpuOptions(in, out, s)
+}
+
+func autoConvert_v1alpha3_DataDisk_To_v1alpha4_DataDisk(in *DataDisk, out *v1alpha4.DataDisk, s conversion.Scope) error {
+	out.Size = in.Size
+	out.SnapshotId = in.SnapshotId
+	out.Category = in.Category
+	out.Encrypted = (*bool)(unsafe.Pointer(in.Encrypted))
+	out.KMSKeyId = in.KMSKeyId
+	out.DiskName = in.DiskName
+	out.Description = in.Description
+	out.DeleteWithInstance = (*bool)(unsafe.Pointer(in.DeleteWithInstance))
+	out.PerformanceLevel = in.PerformanceLevel
+	out.AutoSnapshotPolicyId = in.AutoSnapshotPolicyId
+	return nil
+}
+
+// Convert_v1alpha3_DataDisk_To_v1alpha4_DataDisk is an autogenerated conversion function.
+func Convert_v1alpha3_DataDisk_To_v1alpha4_DataDisk(in *DataDisk, out *v1alpha4.DataDisk, s conversion.Scope) error {
+	return autoConvert_v1alpha3_DataDisk_To_v1alpha4_DataDisk(in, out, s)
+}
+
+func autoConvert_v1alpha4_DataDisk_To_v1alpha3_DataDisk(in *v1alpha4.DataDisk, out *DataDisk, s conversion.Scope) error {
+	out.Size = in.Size
+	out.SnapshotId = in.SnapshotId
+	out.Category = in.Category
+	out.Encrypted = (*bool)(unsafe.Pointer(in.Encrypted))
+	out.KMSKeyId = in.KMSKeyId
+	out.DiskName = in.DiskName
+	out.Description = in.Description
+	out.DeleteWithInstance = (*bool)(unsafe.Pointer(in.DeleteWithInstance))
+	out.PerformanceLevel = in.PerformanceLevel
+	out.AutoSnapshotPolicyId = in.AutoSnapshotPolicyId
+	return nil
+}
+
+// Convert_v1alpha4_DataDisk_To_v1alpha3_DataDisk is an autogenerated conversion function.
+func Convert_v1alpha4_DataDisk_To_v1alpha3_DataDisk(in *v1alpha4.DataDisk, out *DataDisk, s conversion.Scope) error {
+	return autoConvert_v1alpha4_DataDisk_To_v1alpha3_DataDisk(in, out, s)
+}
+
+func autoConvert_v1alpha3_LoginSpec_To_v1alpha4_LoginSpec(in *LoginSpec, out *v1alpha4.LoginSpec, s conversion.Scope) error {
+	out.KeyPair = in.KeyPair
+	out.LoginPassword = in.LoginPassword
+	return nil
+}
+
+// Convert_v1alpha3_LoginSpec_To_v1alpha4_LoginSpec is an autogenerated conversion function.
+func Convert_v1alpha3_LoginSpec_To_v1alpha4_LoginSpec(in *LoginSpec, out *v1alpha4.LoginSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_LoginSpec_To_v1alpha4_LoginSpec(in, out, s)
+}
+
+func autoConvert_v1alpha4_LoginSpec_To_v1alpha3_LoginSpec(in *v1alpha4.LoginSpec, out *LoginSpec, s conversion.Scope) error {
+	out.KeyPair = in.KeyPair
+	out.LoginPassword = in.LoginPassword
+	return nil
+}
+
+// Convert_v1alpha4_LoginSpec_To_v1alpha3_LoginSpec is an autogenerated conversion function.
+func Convert_v1alpha4_LoginSpec_To_v1alpha3_LoginSpec(in *v1alpha4.LoginSpec, out *LoginSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha4_LoginSpec_To_v1alpha3_LoginSpec(in, out, s)
+}
+
+func autoConvert_v1alpha3_MachineNetworkSpec_To_v1alpha4_MachineNetworkSpec(in *MachineNetworkSpec, out *v1alpha4.MachineNetworkSpec, s conversion.Scope) error {
+	out.SecurityGroupId = in.SecurityGroupId
+	out.VSwitchId = in.VSwitchId
+	out.InternetMaxBandwidthIn = in.InternetMaxBandwidthIn
+	out.InternetMaxBandwidthOut = in.InternetMaxBandwidthOut
+	out.InternetChargeType = in.InternetChargeType
+	out.PrivateIpAddress = in.PrivateIpAddress
+	return nil
+}
+
+// Convert_v1alpha3_MachineNetworkSpec_To_v1alpha4_MachineNetworkSpec is an autogenerated conversion function.
+func Convert_v1alpha3_MachineNetworkSpec_To_v1alpha4_MachineNetworkSpec(in *MachineNetworkSpec, out *v1alpha4.MachineNetworkSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_MachineNetworkSpec_To_v1alpha4_MachineNetworkSpec(in, out, s)
+}
+
+func autoConvert_v1alpha4_MachineNetworkSpec_To_v1alpha3_MachineNetworkSpec(in *v1alpha4.MachineNetworkSpec, out *MachineNetworkSpec, s conversion.Scope) error {
+	out.SecurityGroupId = in.SecurityGroupId
+	out.VSwitchId = in.VSwitchId
+	out.InternetMaxBandwidthIn = in.InternetMaxBandwidthIn
+	out.InternetMaxBandwidthOut = in.InternetMaxBandwidthOut
+	out.InternetChargeType = in.InternetChargeType
+	out.PrivateIpAddress = in.PrivateIpAddress
+	return nil
+}
+
+// Convert_v1alpha4_MachineNetworkSpec_To_v1alpha3_MachineNetworkSpec is an autogenerated conversion function.
+func Convert_v1alpha4_MachineNetworkSpec_To_v1alpha3_MachineNetworkSpec(in *v1alpha4.MachineNetworkSpec, out *MachineNetworkSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha4_MachineNetworkSpec_To_v1alpha3_MachineNetworkSpec(in, out, s)
+}
+
+func autoConvert_v1alpha3_MachineVolumeSpec_To_v1alpha4_MachineVolumeSpec(in *MachineVolumeSpec, out *v1alpha4.MachineVolumeSpec, s conversion.Scope) error {
+	if err := Convert_v1alpha3_SystemDisk_To_v1alpha4_SystemDisk(&in.SystemDisk, &out.SystemDisk, s); err != nil {
+		return err
+	}
+	out.DataDisks = *(*[]*v1alpha4.DataDisk)(unsafe.Pointer(&in.DataDisks))
+	out.InternetMaxBandwidthIn = in.InternetMaxBandwidthIn
+	out.InternetMaxBandwidthOut = in.InternetMaxBandwidthOut
+	return nil
+}
+
+// Convert_v1alpha3_MachineVolumeSpec_To_v1alpha4_MachineVolumeSpec is an autogenerated conversion function.
+func Convert_v1alpha3_MachineVolumeSpec_To_v1alpha4_MachineVolumeSpec(in *MachineVolumeSpec, out *v1alpha4.MachineVolumeSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_MachineVolumeSpec_To_v1alpha4_MachineVolumeSpec(in, out, s)
+}
+
+func autoConvert_v1alpha4_MachineVolumeSpec_To_v1alpha3_MachineVolumeSpec(in *v1alpha4.MachineVolumeSpec, out *MachineVolumeSpec, s conversion.Scope) error {
+	if err := Convert_v1alpha4_SystemDisk_To_v1alpha3_SystemDisk(&in.SystemDisk, &out.SystemDisk, s); err != nil {
+		return err
+	}
+	out.DataDisks = *(*[]*DataDisk)(unsafe.Pointer(&in.DataDisks))
+	out.InternetMaxBandwidthIn = in.InternetMaxBandwidthIn
+	out.InternetMaxBandwidthOut = in.InternetMaxBandwidthOut
+	return nil
+}
+
+// Convert_v1alpha4_MachineVolumeSpec_To_v1alpha3_MachineVolumeSpec is an autogenerated conversion function.
+func Convert_v1alpha4_MachineVolumeSpec_To_v1alpha3_MachineVolumeSpec(in *v1alpha4.MachineVolumeSpec, out *MachineVolumeSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha4_MachineVolumeSpec_To_v1alpha3_MachineVolumeSpec(in, out, s)
+}
+
+func autoConvert_v1alpha3_NetworkSpec_To_v1alpha4_NetworkSpec(in *NetworkSpec, out *v1alpha4.NetworkSpec, s conversion.Scope) error {
+	out.VpcId = in.VpcId
+	out.VpcCidr = in.VpcCidr
+	out.MasterVswitchIds = *(*[]string)(unsafe.Pointer(&in.MasterVswitchIds))
+	// WARNING: in.WorkerVswitchds requires manual conversion: does not exist in peer-type
+	out.SnatEntry = (*bool)(unsafe.Pointer(in.SnatEntry))
+	out.ContainerCidr = in.ContainerCidr
+	out.ServiceCidr = in.ServiceCidr
+	out.EndpointPublicAccess = (*bool)(unsafe.Pointer(in.EndpointPublicAccess))
+	return nil
+}
+
+func autoConvert_v1alpha4_NetworkSpec_To_v1alpha3_NetworkSpec(in *v1alpha4.NetworkSpec, out *NetworkSpec, s conversion.Scope) error {
+	out.VpcId = in.VpcId
+	out.VpcCidr = in.VpcCidr
+	out.MasterVswitchIds = *(*[]string)(unsafe.Pointer(&in.MasterVswitchIds))
+	// WARNING: in.WorkerVswitchIds requires manual conversion: does not exist in peer-type
+	out.SnatEntry = (*bool)(unsafe.Pointer(in.SnatEntry))
+	out.ContainerCidr = in.ContainerCidr
+	out.ServiceCidr = in.ServiceCidr
+	out.EndpointPublicAccess = (*bool)(unsafe.Pointer(in.EndpointPublicAccess))
+	return nil
+}
+
+func autoConvert_v1alpha3_SystemDisk_To_v1alpha4_SystemDisk(in *SystemDisk, out *v1alpha4.SystemDisk, s conversion.Scope) error {
+	out.Size = in.Size
+	out.Category = in.Category
+	out.DiskName = in.DiskName
+	out.Description = in.Description
+	out.PerformanceLevel = in.PerformanceLevel
+	out.AutoSnapshotPolicyId = in.AutoSnapshotPolicyId
+	return nil
+}
+
+// Convert_v1alpha3_SystemDisk_To_v1alpha4_SystemDisk is an autogenerated conversion function.
+func Convert_v1alpha3_SystemDisk_To_v1alpha4_SystemDisk(in *SystemDisk, out *v1alpha4.SystemDisk, s conversion.Scope) error {
+	return autoConvert_v1alpha3_SystemDisk_To_v1alpha4_SystemDisk(in, out, s)
+}
+
+func autoConvert_v1alpha4_SystemDisk_To_v1alpha3_SystemDisk(in *v1alpha4.SystemDisk, out *SystemDisk, s conversion.Scope) error {
+	out.Size = in.Size
+	out.Category = in.Category
+	out.DiskName = in.DiskName
+	out.Description = in.Description
+	out.PerformanceLevel = in.PerformanceLevel
+	out.AutoSnapshotPolicyId = in.AutoSnapshotPolicyId
+	return nil
+}
+
+// Convert_v1alpha4_SystemDisk_To_v1alpha3_SystemDisk is an autogenerated conversion function.
+func Convert_v1alpha4_SystemDisk_To_v1alpha3_SystemDisk(in *v1alpha4.SystemDisk, out *SystemDisk, s conversion.Scope) error {
+	return autoConvert_v1alpha4_SystemDisk_To_v1alpha3_SystemDisk(in, out, s)
+}
+
+func autoConvert_v1alpha3_Tags_To_v1alpha4_Tags(in *Tags, out *v1alpha4.Tags, s conversion.Scope) error {
+	// WARNING: in.Key requires manual conversion: does not exist in peer-type
+	// WARNING: in.Value requires manual conversion: does not exist in peer-type
+	return nil
+}
+
+func autoConvert_v1alpha4_Tags_To_v1alpha3_Tags(in *v1alpha4.Tags, out *Tags, s conversion.Scope) error {
+	// FIXME: Type v1alpha4.Tags is unsupported.
+	return nil
+}
+
+func autoConvert_v1alpha3_UserData_To_v1alpha4_UserData(in *UserData, out *v1alpha4.UserData, s conversion.Scope) error {
+	out.Encryped = (*bool)(unsafe.Pointer(in.Encryped))
+	out.Datas = in.Datas
+	return nil
+}
+
+// Convert_v1alpha3_UserData_To_v1alpha4_UserData is an autogenerated conversion function.
+func Convert_v1alpha3_UserData_To_v1alpha4_UserData(in *UserData, out *v1alpha4.UserData, s conversion.Scope) error {
+	return autoConvert_v1alpha3_UserData_To_v1alpha4_UserData(in, out, s)
+}
+
+func autoConvert_v1alpha4_UserData_To_v1alpha3_UserData(in *v1alpha4.UserData, out *UserData, s conversion.Scope) error {
+	out.Encryped = (*bool)(unsafe.Pointer(in.Encryped))
+	out.Datas = in.Datas
+	return nil
+}
+
+// Convert_v1alpha4_UserData_To_v1alpha3_UserData is an autogenerated conversion function.
+func Convert_v1alpha4_UserData_To_v1alpha3_UserData(in *v1alpha4.UserData, out *UserData, s conversion.Scope) error {
+	return autoConvert_v1alpha4_UserData_To_v1alpha3_UserData(in, out, s)
+}
+
+func autoConvert_v1alpha3_VolumeSpec_To_v1alpha4_VolumeSpec(in *VolumeSpec, out *v1alpha4.VolumeSpec, s conversion.Scope) error {
+	if err := Convert_v1alpha3_ClusterSystemDisk_To_v1alpha4_ClusterSystemDisk(&in.MasterSystemDisk, &out.MasterSystemDisk, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_ClusterSystemDisk_To_v1alpha4_ClusterSystemDisk(&in.WorkerSystemDisk, &out.WorkerSystemDisk, s); err != nil {
+		return err
+	}
+	out.DataDisk = *(*[]v1alpha4.ClusterDataDisk)(unsafe.Pointer(&in.DataDisk))
+	return nil
+}
+
+// Convert_v1alpha3_VolumeSpec_To_v1alpha4_VolumeSpec is an autogenerated conversion function.
+func Convert_v1alpha3_VolumeSpec_To_v1alpha4_VolumeSpec(in *VolumeSpec, out *v1alpha4.VolumeSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_VolumeSpec_To_v1alpha4_VolumeSpec(in, out, s)
+}
+
+func autoConvert_v1alpha4_VolumeSpec_To_v1alpha3_VolumeSpec(in *v1alpha4.VolumeSpec, out *VolumeSpec, s conversion.Scope) error {
+	if err := Convert_v1alpha4_ClusterSystemDisk_To_v1alpha3_ClusterSystemDisk(&in.MasterSystemDisk, &out.MasterSystemDisk, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha4_ClusterSystemDisk_To_v1alpha3_ClusterSystemDisk(&in.WorkerSystemDisk, &out.WorkerSystemDisk, s); err != nil {
+		return err
+	}
+	out.DataDisk = *(*[]ClusterDataDisk)(unsafe.Pointer(&in.DataDisk))
+	return nil
+}
+
+// Convert_v1alpha4_VolumeSpec_To_v1alpha3_VolumeSpec is an autogenerated conversion function.
+func Convert_v1alpha4_VolumeSpec_To_v1alpha3_VolumeSpec(in *v1alpha4.VolumeSpec, out *VolumeSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha4_VolumeSpec_To_v1alpha3_VolumeSpec(in, out, s)
+}
+
+func autoConvert_v1alpha3_VpcAttributes_To_v1alpha4_VpcAttributes(in *VpcAttributes, out *v1alpha4.VpcAttributes, s conversion.Scope) error {
+	out.NatIpAddress = in.NatIpAddress
+	out.PrivateIpAddress = *(*[]string)(unsafe.Pointer(&in.PrivateIpAddress))
+	out.VSwitchId = in.VSwitchId
+	out.VpcId = in.VpcId
+	return nil
+}
+
+// Convert_v1alpha3_VpcAttributes_To_v1alpha4_VpcAttributes is an autogenerated conversion function.
+func Convert_v1alpha3_VpcAttributes_To_v1alpha4_VpcAttributes(in *VpcAttributes, out *v1alpha4.VpcAttributes, s conversion.Scope) error {
+	return autoConvert_v1alpha3_VpcAttributes_To_v1alpha4_VpcAttributes(in, out, s)
+}
+
+func autoConvert_v1alpha4_VpcAttributes_To_v1alpha3_VpcAttributes(in *v1alpha4.VpcAttributes, out *VpcAttributes, s conversion.Scope) error {
+	out.NatIpAddress = in.NatIpAddress
+	out.PrivateIpAddress = *(*[]string)(unsafe.Pointer(&in.PrivateIpAddress))
+	out.VSwitchId = in.VSwitchId
+	out.VpcId = in.VpcId
+	return nil
+}
+
+// Convert_v1alpha4_VpcAttributes_To_v1alpha3_VpcAttributes is an autogenerated conversion function.
+func Convert_v1alpha4_VpcAttributes_To_v1alpha3_VpcAttributes(in *v1alpha4.VpcAttributes, out *VpcAttributes, s conversion.Scope) error {
+	return autoConvert_v1alpha4_VpcAttributes_To_v1alpha3_VpcAttributes(in, out, s)
+}
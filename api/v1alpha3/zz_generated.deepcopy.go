@@ -1,3 +1,4 @@
+//go:build !ignore_autogenerated
 // +build !ignore_autogenerated
 
 /*
@@ -22,6 +23,7 @@ package v1alpha3
 
 import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/cluster-api/errors"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -29,8 +31,8 @@ func (in *ACKCluster) DeepCopyInto(out *ACKCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACKCluster.
@@ -86,6 +88,12 @@ func (in *ACKClusterList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ACKClusterSpec) DeepCopyInto(out *ACKClusterSpec) {
 	*out = *in
+	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	out.LoginSpec = in.LoginSpec
+	in.VolumeSpec.DeepCopyInto(&out.VolumeSpec)
+	in.NetworkSpec.DeepCopyInto(&out.NetworkSpec)
+	out.Addons = in.Addons
+	out.Tags = in.Tags
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACKClusterSpec.
@@ -101,6 +109,16 @@ func (in *ACKClusterSpec) DeepCopy() *ACKClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ACKClusterStatus) DeepCopyInto(out *ACKClusterStatus) {
 	*out = *in
+	if in.MasterInstanceIDs != nil {
+		in, out := &in.MasterInstanceIDs, &out.MasterInstanceIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeInstanceIDs != nil {
+		in, out := &in.NodeInstanceIDs, &out.NodeInstanceIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACKClusterStatus.
@@ -118,8 +136,8 @@ func (in *ACKMachine) DeepCopyInto(out *ACKMachine) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACKMachine.
@@ -175,6 +193,15 @@ func (in *ACKMachineList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ACKMachineSpec) DeepCopyInto(out *ACKMachineSpec) {
 	*out = *in
+	if in.ProviderID != nil {
+		in, out := &in.ProviderID, &out.ProviderID
+		*out = new(string)
+		**out = **in
+	}
+	out.Tags = in.Tags
+	in.UserData.DeepCopyInto(&out.UserData)
+	out.MachineNetworkSpec = in.MachineNetworkSpec
+	in.MachineVolumeSpec.DeepCopyInto(&out.MachineVolumeSpec)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACKMachineSpec.
@@ -190,6 +217,21 @@ func (in *ACKMachineSpec) DeepCopy() *ACKMachineSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ACKMachineStatus) DeepCopyInto(out *ACKMachineStatus) {
 	*out = *in
+	if in.InstanceState != nil {
+		in, out := &in.InstanceState, &out.InstanceState
+		*out = new(InstanceState)
+		**out = **in
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACKMachineStatus.
@@ -202,6 +244,71 @@ func (in *ACKMachineStatus) DeepCopy() *ACKMachineStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Addons) DeepCopyInto(out *Addons) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Addons.
+func (in *Addons) DeepCopy() *Addons {
+	if in == nil {
+		return nil
+	}
+	out := new(Addons)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDataDisk) DeepCopyInto(out *ClusterDataDisk) {
+	*out = *in
+	if in.Encrypted != nil {
+		in, out := &in.Encrypted, &out.Encrypted
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDataDisk.
+func (in *ClusterDataDisk) DeepCopy() *ClusterDataDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDataDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSystemDisk) DeepCopyInto(out *ClusterSystemDisk) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSystemDisk.
+func (in *ClusterSystemDisk) DeepCopy() *ClusterSystemDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSystemDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CpuOptions) DeepCopyInto(out *CpuOptions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CpuOptions.
+func (in *CpuOptions) DeepCopy() *CpuOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CpuOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataDisk) DeepCopyInto(out *DataDisk) {
 	*out = *in
@@ -227,6 +334,185 @@ func (in *DataDisk) DeepCopy() *DataDisk {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EipAddress) DeepCopyInto(out *EipAddress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EipAddress.
+func (in *EipAddress) DeepCopy() *EipAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(EipAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Instance) DeepCopyInto(out *Instance) {
+	*out = *in
+	if in.SecurityGroupIDs != nil {
+		in, out := &in.SecurityGroupIDs, &out.SecurityGroupIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UserData != nil {
+		in, out := &in.UserData, &out.UserData
+		*out = new(string)
+		**out = **in
+	}
+	if in.InnerIpAddress != nil {
+		in, out := &in.InnerIpAddress, &out.InnerIpAddress
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PublicIpAddress != nil {
+		in, out := &in.PublicIpAddress, &out.PublicIpAddress
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.EipAddress = in.EipAddress
+	if in.DeviceAvailable != nil {
+		in, out := &in.DeviceAvailable, &out.DeviceAvailable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]*Tag, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Tag)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Instance.
+func (in *Instance) DeepCopy() *Instance {
+	if in == nil {
+		return nil
+	}
+	out := new(Instance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoginSpec) DeepCopyInto(out *LoginSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginSpec.
+func (in *LoginSpec) DeepCopy() *LoginSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LoginSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineNetworkSpec) DeepCopyInto(out *MachineNetworkSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineNetworkSpec.
+func (in *MachineNetworkSpec) DeepCopy() *MachineNetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineNetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineVolumeSpec) DeepCopyInto(out *MachineVolumeSpec) {
+	*out = *in
+	out.SystemDisk = in.SystemDisk
+	if in.DataDisks != nil {
+		in, out := &in.DataDisks, &out.DataDisks
+		*out = make([]*DataDisk, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(DataDisk)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineVolumeSpec.
+func (in *MachineVolumeSpec) DeepCopy() *MachineVolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineVolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkInterface) DeepCopyInto(out *NetworkInterface) {
+	*out = *in
+	if in.SecurityGroupIds != nil {
+		in, out := &in.SecurityGroupIds, &out.SecurityGroupIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkInterface.
+func (in *NetworkInterface) DeepCopy() *NetworkInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkSpec) DeepCopyInto(out *NetworkSpec) {
+	*out = *in
+	if in.MasterVswitchIds != nil {
+		in, out := &in.MasterVswitchIds, &out.MasterVswitchIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WorkerVswitchds != nil {
+		in, out := &in.WorkerVswitchds, &out.WorkerVswitchds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SnatEntry != nil {
+		in, out := &in.SnatEntry, &out.SnatEntry
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EndpointPublicAccess != nil {
+		in, out := &in.EndpointPublicAccess, &out.EndpointPublicAccess
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkSpec.
+func (in *NetworkSpec) DeepCopy() *NetworkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemDisk) DeepCopyInto(out *SystemDisk) {
 	*out = *in
@@ -241,3 +527,97 @@ func (in *SystemDisk) DeepCopy() *SystemDisk {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tag) DeepCopyInto(out *Tag) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tag.
+func (in *Tag) DeepCopy() *Tag {
+	if in == nil {
+		return nil
+	}
+	out := new(Tag)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tags) DeepCopyInto(out *Tags) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tags.
+func (in *Tags) DeepCopy() *Tags {
+	if in == nil {
+		return nil
+	}
+	out := new(Tags)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserData) DeepCopyInto(out *UserData) {
+	*out = *in
+	if in.Encryped != nil {
+		in, out := &in.Encryped, &out.Encryped
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserData.
+func (in *UserData) DeepCopy() *UserData {
+	if in == nil {
+		return nil
+	}
+	out := new(UserData)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSpec) DeepCopyInto(out *VolumeSpec) {
+	*out = *in
+	out.MasterSystemDisk = in.MasterSystemDisk
+	out.WorkerSystemDisk = in.WorkerSystemDisk
+	if in.DataDisk != nil {
+		in, out := &in.DataDisk, &out.DataDisk
+		*out = make([]ClusterDataDisk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSpec.
+func (in *VolumeSpec) DeepCopy() *VolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VpcAttributes) DeepCopyInto(out *VpcAttributes) {
+	*out = *in
+	if in.PrivateIpAddress != nil {
+		in, out := &in.PrivateIpAddress, &out.PrivateIpAddress
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VpcAttributes.
+func (in *VpcAttributes) DeepCopy() *VpcAttributes {
+	if in == nil {
+		return nil
+	}
+	out := new(VpcAttributes)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/errors"
+)
+
+// ACKClusterSpec defines the desired state of ACKCluster
+type ACKClusterSpec struct {
+	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
+	// +optional
+	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
+
+	// cluster
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// +optional
+	ClusterType string `json:"clusterType,omitempty"`
+	// +optional
+	RegionId string `json:"regionId,omitempty"`
+	// 资源组ID，集群的所有资源都创建在该资源组中。
+	// +optional
+	ResourceGroupId string `json:"resourceGroupId,omitempty"`
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// +kubebuilder:validation:Enum=none;static
+	// +optional
+	CpuPolicy string `json:"cpuPolicy,omitempty"`
+	// +optional
+	MasterInstanceType string `json:"masterInstanceType,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NodesNum int64 `json:"nodesNum,omitempty"`
+	// +optional
+	WorkerInstanceType string `json:"workerInstanceType,omitempty"`
+
+	// login
+	// +optional
+	LoginSpec LoginSpec `json:"loginSpec,omitempty"`
+	// volume
+	// +optional
+	VolumeSpec VolumeSpec `json:"volumeSpec,omitempty"`
+	// network
+	// +optional
+	NetworkSpec NetworkSpec `json:"networkSpec,omitempty"`
+	// +optional
+	Addons Addons `json:"addons,omitempty"`
+
+	// +optional
+	Tags Tags `json:"tags,omitempty"`
+}
+
+type LoginSpec struct {
+	// +optional
+	KeyPair string `json:"keyPair,omitempty"`
+	// +optional
+	LoginPassword string `json:"loginPassword,omitempty"`
+}
+
+type NetworkSpec struct {
+	// VPC ID，可空。如果不设置，系统会自动创建VPC，系统创建的VPC网段为192.168.0.0/16。
+	// 说明 VpcId 和 vswitchid 只能同时为空或者同时都设置对应的值。
+	// +optional
+	VpcId string `json:"vpcId,omitempty"`
+	// VPC网段，仅在系统自动创建VPC时使用，默认为192.168.0.0/16。
+	// +optional
+	VpcCidr string `json:"vpcCidr,omitempty"`
+	// +optional
+	MasterVswitchIds []string `json:"masterVswitchIds,omitempty"`
+	// +optional
+	WorkerVswitchIds []string `json:"workerVswitchIds,omitempty"`
+
+	// +optional
+	SnatEntry *bool `json:"snatEntry,omitempty"`
+	// 容器网段，不能和VPC网段冲突。当选择系统自动创建VPC时，默认使用172.16.0.0/16网段。
+	// +optional
+	ContainerCidr string `json:"containerCidr,omitempty"`
+	// 服务网段，不能和VPC网段以及容器网段冲突。当选择系统自动创建VPC时，默认使用172.19.0.0/20网段。
+	// +optional
+	ServiceCidr string `json:"serviceCidr,omitempty"`
+
+	// +optional
+	EndpointPublicAccess *bool `json:"endpointPublicAccess,omitempty"`
+}
+
+type VolumeSpec struct {
+	// +optional
+	MasterSystemDisk ClusterSystemDisk `json:"masterSystemDisk,omitempty"`
+	// +optional
+	WorkerSystemDisk ClusterSystemDisk `json:"workerSystemDisk,omitempty"`
+	// +optional
+	DataDisk []ClusterDataDisk `json:"dataDisk,omitempty"`
+}
+
+// system_disk
+type ClusterSystemDisk struct {
+	// +kubebuilder:validation:Enum=cloud;cloud_efficiency;cloud_ssd;cloud_essd
+	// +optional
+	SystemDiskCategory string `json:"systemDiskCategory,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]+$`
+	// +optional
+	SystemDiskSize string `json:"systemDiskSize,omitempty"`
+}
+
+// 挂载盘参数
+type ClusterDataDisk struct {
+	// category：数据盘类型。取值范围：
+	//cloud：普通云盘
+	//cloud_efficiency：高效云盘
+	//cloud_ssd：SSD云盘
+	// +kubebuilder:validation:Enum=cloud;cloud_efficiency;cloud_ssd;cloud_essd
+	// +optional
+	Category string `json:"category,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Size int64 `json:"size,omitempty"`
+
+	// +optional
+	Encrypted *bool `json:"encrypted,omitempty"`
+}
+
+type Addons struct {
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Version string `json:"version,omitempty"`
+	// +optional
+	Config string `json:"config,omitempty"`
+}
+
+// ACKClusterStatus defines the observed state of ACKCluster
+type ACKClusterStatus struct {
+	Ready bool `json:"ready"`
+	// +optional
+	MasterInstanceIDs []string `json:"masterInstanceIDs,omitempty"`
+	// +optional
+	NodeInstanceIDs []string `json:"nodeInstanceIDs,omitempty"`
+	// +optional
+	ScalingGroupID string `json:"scalingGroupID,omitempty"`
+	// 专有网络
+	// +optional
+	VpcId string `json:"vpcId,omitempty"`
+	// 虚拟交换机
+	// +optional
+	VSwitchIds string `json:"vSwitchIds,omitempty"`
+	// +optional
+	IntranetSlbId string `json:"intranetSlbId,omitempty"`
+	// MasterVSwitches are the master vswitches with their zones, control plane machines
+	// without a vswitch are placed in one of them.
+	// +optional
+	MasterVSwitches []VSwitch `json:"masterVSwitches,omitempty"`
+	// WorkerVSwitches are the worker vswitches with their zones, machines without a
+	// vswitch are placed in one of them.
+	// +optional
+	WorkerVSwitches []VSwitch `json:"workerVSwitches,omitempty"`
+	// FailureDomains are the zones of the cluster vswitches, the zones of the master
+	// vswitches are eligible for control plane machines.
+	// +optional
+	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
+
+	// FailureReason is set when reconciling the ACKCluster failed terminally,
+	// e.g. because of invalid parameters or exceeded quotas.
+	// +optional
+	FailureReason *errors.ClusterStatusError `json:"failureReason,omitempty"`
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ackclusters,scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+
+// ACKCluster is the Schema for the ackclusters API
+type ACKCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ACKClusterSpec   `json:"spec,omitempty"`
+	Status ACKClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ACKClusterList contains a list of ACKCluster
+type ACKClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ACKCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ACKCluster{}, &ACKClusterList{})
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func (r *ACKCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+const (
+	// DefaultVpcCidr is the cidr of the VPC created by the system.
+	DefaultVpcCidr = "192.168.0.0/16"
+	// DefaultContainerCidr is the container cidr used together with a VPC created by the system.
+	DefaultContainerCidr = "172.16.0.0/16"
+	// DefaultServiceCidr is the service cidr used together with a VPC created by the system.
+	DefaultServiceCidr = "172.19.0.0/20"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-ack-cluster-k8s-io-v1alpha4-ackcluster,mutating=false,failurePolicy=fail,groups=ack.cluster.k8s.io,resources=ackclusters,versions=v1alpha4,name=validation.ackcluster.ack.cluster.k8s.io
+
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-ack-cluster-k8s-io-v1alpha4-ackcluster,mutating=true,failurePolicy=fail,groups=ack.cluster.k8s.io,resources=ackclusters,versions=v1alpha4,name=default.ackcluster.ack.cluster.k8s.io
+
+var _ webhook.Validator = &ACKCluster{}
+var _ webhook.Defaulter = &ACKCluster{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *ACKCluster) Default() {
+	network := &r.Spec.NetworkSpec
+	// the cidrs are only defaulted when the VPC is created by the system
+	if network.VpcId == "" {
+		if network.VpcCidr == "" {
+			network.VpcCidr = DefaultVpcCidr
+		}
+		if network.ContainerCidr == "" {
+			network.ContainerCidr = DefaultContainerCidr
+		}
+		if network.ServiceCidr == "" {
+			network.ServiceCidr = DefaultServiceCidr
+		}
+	}
+
+	volume := &r.Spec.VolumeSpec
+	if volume.MasterSystemDisk.SystemDiskCategory == "" {
+		volume.MasterSystemDisk.SystemDiskCategory = string(DiskCategoryCloudEfficiency)
+	}
+	if volume.WorkerSystemDisk.SystemDiskCategory == "" {
+		volume.WorkerSystemDisk.SystemDiskCategory = string(DiskCategoryCloudEfficiency)
+	}
+	for i := range volume.DataDisk {
+		if volume.DataDisk[i].Category == "" {
+			volume.DataDisk[i].Category = string(DiskCategoryCloudEfficiency)
+		}
+	}
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ACKCluster) ValidateCreate() error {
+	return r.validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ACKCluster) ValidateUpdate(old runtime.Object) error {
+	oldC, ok := old.(*ACKCluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an ACKCluster but got a %T", old))
+	}
+	// the old object may have been stored before the defaulting webhook was deployed, the
+	// fields it defaults are compared as if it had been defaulted already
+	defaulted := oldC.DeepCopy()
+	defaulted.Default()
+	return r.validate(defaulted)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ACKCluster) ValidateDelete() error {
+	return nil
+}
+
+func (r *ACKCluster) validate(old *ACKCluster) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateNetworkSpec(&r.Spec.NetworkSpec, specPath.Child("networkSpec"))...)
+	allErrs = append(allErrs, validateVolumeSpec(&r.Spec.VolumeSpec, specPath.Child("volumeSpec"))...)
+
+	if old != nil {
+		if old.Spec.RegionId != r.Spec.RegionId {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("regionId"), "field is immutable"))
+		}
+		if !reflect.DeepEqual(old.Spec.NetworkSpec, r.Spec.NetworkSpec) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("networkSpec"), "field is immutable"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("ACKCluster").GroupKind(), r.Name, allErrs)
+}
+
+func validateNetworkSpec(spec *NetworkSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// VpcId and the vswitch ids must be either both empty or both set.
+	if (spec.VpcId == "") != (len(spec.MasterVswitchIds) == 0) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("vpcId"), spec.VpcId,
+			"vpcId and masterVswitchIds must be either both empty or both set"))
+	}
+
+	var vpcCidr, containerCidr, serviceCidr *net.IPNet
+	if spec.VpcCidr != "" {
+		_, cidr, err := net.ParseCIDR(spec.VpcCidr)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("vpcCidr"), spec.VpcCidr, err.Error()))
+		}
+		vpcCidr = cidr
+	}
+	if spec.ContainerCidr != "" {
+		_, cidr, err := net.ParseCIDR(spec.ContainerCidr)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("containerCidr"), spec.ContainerCidr, err.Error()))
+		}
+		containerCidr = cidr
+	}
+	if spec.ServiceCidr != "" {
+		_, cidr, err := net.ParseCIDR(spec.ServiceCidr)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("serviceCidr"), spec.ServiceCidr, err.Error()))
+		}
+		serviceCidr = cidr
+	}
+	if vpcCidr != nil && containerCidr != nil && cidrsOverlap(vpcCidr, containerCidr) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("containerCidr"), spec.ContainerCidr,
+			fmt.Sprintf("must not overlap with vpcCidr %s", spec.VpcCidr)))
+	}
+	if vpcCidr != nil && serviceCidr != nil && cidrsOverlap(vpcCidr, serviceCidr) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("serviceCidr"), spec.ServiceCidr,
+			fmt.Sprintf("must not overlap with vpcCidr %s", spec.VpcCidr)))
+	}
+	if containerCidr != nil && serviceCidr != nil && cidrsOverlap(containerCidr, serviceCidr) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("serviceCidr"), spec.ServiceCidr,
+			fmt.Sprintf("must not overlap with containerCidr %s", spec.ContainerCidr)))
+	}
+
+	return allErrs
+}
+
+func validateVolumeSpec(spec *VolumeSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateClusterSystemDisk(&spec.MasterSystemDisk, fldPath.Child("masterSystemDisk"))...)
+	allErrs = append(allErrs, validateClusterSystemDisk(&spec.WorkerSystemDisk, fldPath.Child("workerSystemDisk"))...)
+	for i := range spec.DataDisk {
+		disk := spec.DataDisk[i]
+		diskPath := fldPath.Child("dataDisk").Index(i)
+		allErrs = append(allErrs, validateDiskCategory(disk.Category, diskPath.Child("category"))...)
+		allErrs = append(allErrs, validateDataDiskSize(disk.Category, disk.Size, diskPath.Child("size"))...)
+	}
+
+	return allErrs
+}
+
+func validateClusterSystemDisk(disk *ClusterSystemDisk, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateDiskCategory(disk.SystemDiskCategory, fldPath.Child("systemDiskCategory"))...)
+	allErrs = append(allErrs, validateSystemDiskSize(disk.SystemDiskSize, fldPath.Child("systemDiskSize"))...)
+
+	return allErrs
+}
+
+func cidrsOverlap(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestCidrsOverlap(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		overlap bool
+	}{
+		{name: "disjoint", a: "192.168.0.0/16", b: "172.16.0.0/16"},
+		{name: "adjacent", a: "172.16.0.0/16", b: "172.17.0.0/16"},
+		{name: "equal", a: "10.0.0.0/8", b: "10.0.0.0/8", overlap: true},
+		{name: "first contains second", a: "10.0.0.0/8", b: "10.1.0.0/16", overlap: true},
+		{name: "second contains first", a: "10.1.0.0/16", b: "10.0.0.0/8", overlap: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			_, a, err := net.ParseCIDR(tt.a)
+			g.Expect(err).NotTo(HaveOccurred())
+			_, b, err := net.ParseCIDR(tt.b)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cidrsOverlap(a, b)).To(Equal(tt.overlap))
+		})
+	}
+}
+
+func TestValidateNetworkSpec(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  NetworkSpec
+		valid bool
+	}{
+		{name: "defaults", spec: NetworkSpec{VpcCidr: DefaultVpcCidr, ContainerCidr: DefaultContainerCidr, ServiceCidr: DefaultServiceCidr}, valid: true},
+		{name: "existing vpc", spec: NetworkSpec{VpcId: "vpc-1", MasterVswitchIds: []string{"vsw-1"}}, valid: true},
+		{name: "vpc without vswitches", spec: NetworkSpec{VpcId: "vpc-1"}},
+		{name: "vswitches without vpc", spec: NetworkSpec{MasterVswitchIds: []string{"vsw-1"}}},
+		{name: "invalid vpc cidr", spec: NetworkSpec{VpcCidr: "192.168.0.0"}},
+		{name: "invalid container cidr", spec: NetworkSpec{ContainerCidr: "172.16.0.0/33"}},
+		{name: "invalid service cidr", spec: NetworkSpec{ServiceCidr: "service"}},
+		{name: "container cidr in vpc cidr", spec: NetworkSpec{VpcCidr: "10.0.0.0/8", ContainerCidr: "10.16.0.0/16"}},
+		{name: "service cidr in vpc cidr", spec: NetworkSpec{VpcCidr: "10.0.0.0/8", ServiceCidr: "10.19.0.0/20"}},
+		{name: "service cidr in container cidr", spec: NetworkSpec{ContainerCidr: "172.16.0.0/12", ServiceCidr: "172.19.0.0/20"}},
+		{name: "container cidr with existing vpc", spec: NetworkSpec{VpcId: "vpc-1", MasterVswitchIds: []string{"vsw-1"}, ContainerCidr: "172.16.0.0/16", ServiceCidr: "172.19.0.0/20"}, valid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateNetworkSpec(&tt.spec, field.NewPath("networkSpec"))
+			if tt.valid {
+				g.Expect(errs).To(BeEmpty())
+			} else {
+				g.Expect(errs).NotTo(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestACKClusterValidateUpdateUndefaulted(t *testing.T) {
+	g := NewWithT(t)
+
+	// an ACKCluster stored before the defaulting webhook was deployed
+	old := &ACKCluster{
+		Spec: ACKClusterSpec{
+			RegionId: "cn-hangzhou",
+		},
+	}
+	// the defaulting webhook runs before the validating webhook on the next update
+	updated := old.DeepCopy()
+	updated.Default()
+	updated.Finalizers = []string{"ackcluster.ack.cluster.k8s.io"}
+
+	g.Expect(updated.ValidateUpdate(old)).To(Succeed())
+
+	// the defaulted cidrs stay immutable
+	changed := updated.DeepCopy()
+	changed.Spec.NetworkSpec.ServiceCidr = "172.22.0.0/20"
+	g.Expect(changed.ValidateUpdate(old)).NotTo(Succeed())
+}
+
+func TestACKClusterValidateUpdate(t *testing.T) {
+	old := &ACKCluster{
+		Spec: ACKClusterSpec{
+			RegionId: "cn-hangzhou",
+		},
+	}
+	old.Default()
+
+	tests := []struct {
+		name   string
+		update func(c *ACKCluster)
+		valid  bool
+	}{
+		{name: "unchanged", update: func(c *ACKCluster) {}, valid: true},
+		{name: "worker system disk changed", update: func(c *ACKCluster) {
+			c.Spec.VolumeSpec.WorkerSystemDisk.SystemDiskCategory = string(DiskCategoryCloudSSD)
+		}, valid: true},
+		{name: "region changed", update: func(c *ACKCluster) { c.Spec.RegionId = "cn-beijing" }},
+		{name: "vpc cidr changed", update: func(c *ACKCluster) { c.Spec.NetworkSpec.VpcCidr = "10.0.0.0/8" }},
+		{name: "container cidr changed", update: func(c *ACKCluster) { c.Spec.NetworkSpec.ContainerCidr = "172.20.0.0/16" }},
+		{name: "snat entry changed", update: func(c *ACKCluster) {
+			snat := false
+			c.Spec.NetworkSpec.SnatEntry = &snat
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			updated := old.DeepCopy()
+			tt.update(updated)
+			if tt.valid {
+				g.Expect(updated.ValidateUpdate(old)).To(Succeed())
+			} else {
+				g.Expect(updated.ValidateUpdate(old)).NotTo(Succeed())
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ACKImageSpec defines the desired state of ACKImage
+type ACKImageSpec struct {
+	// 源镜像所属的地域ID。
+	RegionId string `json:"regionId"`
+	// 源镜像ID。
+	ImageId string `json:"imageId"`
+	// Regions are the target regions the image is copied to.
+	// +kubebuilder:validation:MinItems=1
+	Regions []string `json:"regions"`
+	// 目标镜像的名称，默认为源镜像ID。
+	// +optional
+	ImageName string `json:"imageName,omitempty"`
+	// 目标镜像的描述信息。
+	// +optional
+	Description string `json:"description,omitempty"`
+	// +optional
+	Tags Tags `json:"tags,omitempty"`
+}
+
+// ACKImageStatus defines the observed state of ACKImage
+type ACKImageStatus struct {
+	// Ready is true when the image is available in all target regions.
+	Ready bool `json:"ready"`
+	// Images maps the regions to the IDs of the available images, including the source region.
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+	// PendingImages maps the regions to the IDs of the images being copied.
+	// +optional
+	PendingImages map[string]string `json:"pendingImages,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ackimages,scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Image",type="string",JSONPath=".spec.imageId"
+// +kubebuilder:printcolumn:name="Ready",type="boolean",JSONPath=".status.ready"
+
+// ACKImage copies a source image into the target regions, so machines in these regions
+// can be created from the local copy.
+type ACKImage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ACKImageSpec   `json:"spec,omitempty"`
+	Status ACKImageStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ACKImageList contains a list of ACKImage
+type ACKImageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ACKImage `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ACKImage{}, &ACKImageList{})
+}
@@ -0,0 +1,465 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/errors"
+)
+
+const MachineFinalizer = "ackmachine.infrastructure.cluster.x-k8s.io"
+
+// FilesystemResizePendingAnnotation records the disks resized online whose partitions and
+// filesystems still have to be grown on the node. Remove it once they have been grown.
+const FilesystemResizePendingAnnotation = "ack.cluster.k8s.io/filesystem-resize-pending"
+
+// ACKMachineSpec defines the desired state of ACKMachine
+// check for deatails: https://help.aliyun.com/document_detail/63440.html
+type ACKMachineSpec struct {
+	// ProviderID is the unique identifier as specified by the cloud provider.
+	// +optional
+	ProviderID *string `json:"providerID,omitempty"`
+
+	// +optional
+	ClusterId string `json:"clusterId,omitempty"`
+	// +optional
+	RegionId string `json:"regionId,omitempty"`
+	// +optional
+	ZoneId string `json:"zoneId,omitempty"`
+	// ZoneIds are the acceptable zones when ZoneId is empty, the instance is created in one
+	// of them in which the instance type has stock.
+	// +optional
+	ZoneIds []string `json:"zoneIds,omitempty"`
+	// 资源组ID，默认为ACKCluster的资源组。
+	// +optional
+	ResourceGroupId string `json:"resourceGroupId,omitempty"`
+	// LaunchTemplate references an existing ECS launch template to create the instance from.
+	// 显式设置的ACKMachine字段会覆盖启动模板中的对应配置。
+	// +optional
+	LaunchTemplate *LaunchTemplateReference `json:"launchTemplate,omitempty"`
+	// 实例的资源规格。如果您不指定LaunchTemplateId或LaunchTemplateName以确定启动模板，InstanceType为必选参数。
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+	// InstanceTypes are further acceptable instance types in order of preference, the first
+	// one with stock is used if InstanceType has none.
+	// +optional
+	InstanceTypes []string `json:"instanceTypes,omitempty"`
+	// +optional
+	InstanceName string `json:"instanceName,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+	// +kubebuilder:validation:Enum=none;optimized
+	// +optional
+	IoOptimized string `json:"ioOptimized,omitempty"`
+
+	// +optional
+	ImageId string `json:"imageId,omitempty"`
+	// ImageLookup resolves the newest matching image when ImageId is empty, the resolved
+	// image ID is recorded in status.
+	// +optional
+	ImageLookup *ImageLookup `json:"imageLookup,omitempty"`
+	// ImageRef references an ACKImage in the namespace of the ACKMachine, its copy in the
+	// region of the ACKMachine is used when ImageId is empty.
+	// +optional
+	ImageRef *corev1.LocalObjectReference `json:"imageRef,omitempty"`
+	// +optional
+	Tags Tags `json:"tags,omitempty"`
+	// +optional
+	UserData UserData `json:"userData,omitempty"`
+
+	// +optional
+	MachineNetworkSpec MachineNetworkSpec `json:"machineNetworkSpec,omitempty"`
+	// +optional
+	MachineVolumeSpec MachineVolumeSpec `json:"machineVolumeSpec,omitempty"`
+
+	// NetworkInterfaces are the secondary elastic network interfaces attached to the instance,
+	// they are created unless an existing network interface ID is given.
+	// +optional
+	NetworkInterfaces []NetworkInterface `json:"networkInterfaces,omitempty"`
+
+	// EIP associates an elastic IP address with the instance.
+	// +optional
+	EIP *EIPSpec `json:"eip,omitempty"`
+
+	// SpotOptions allows the ACKMachine to run as a preemptible instance.
+	// 抢占式实例仅支持按量付费，被回收后ACKMachine会被标记为失败，由MachineHealthCheck替换。
+	// +optional
+	SpotOptions *SpotOptions `json:"spotOptions,omitempty"`
+
+	// charge related
+	// 实例的付费方式。取值范围：PrePaid：包年包月。PostPaid（默认）：按量付费。
+	// 包年包月实例在删除ACKMachine时会先转换为按量付费再释放。
+	// +kubebuilder:validation:Enum=PrePaid;PostPaid
+	// +optional
+	InstanceChargeType string `json:"instanceChargeType,omitempty"`
+	// 购买资源的时长，单位为：PeriodUnit。当参数InstanceChargeType取值为PrePaid时才生效且为必选值。
+	// PeriodUnit为Week时，Period取值{"1", "2", "3", "4"}。
+	// PeriodUnit为Month时，Period取值{"1", "2", "3", "4", "5", "6", "7", "8", "9", "12", "24", "36", "48", "60"}。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Period int64 `json:"period,omitempty"`
+	// 包年包月计费方式的时长单位。取值范围：Week，Month（默认）。
+	// +kubebuilder:validation:Enum=Week;Month
+	// +optional
+	PeriodUnit string `json:"periodUnit,omitempty"`
+	// 是否要自动续费。当参数InstanceChargeType取值PrePaid时才生效。
+	// 取值范围：true：自动续费。false（默认）：不自动续费。
+	// +optional
+	AutoRenew bool `json:"autoRenew,omitempty"`
+	// 每次自动续费的时长，当参数AutoRenew取值True时为必填。
+	// PeriodUnit为Week时，AutoRenewPeriod取值{"1", "2", "3"}。
+	//PeriodUnit为Month时，AutoRenewPeriod取值{"1", "2", "3", "6", "12"}。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	AutoRenewPeriod int64 `json:"autoRenewPeriod,omitempty"`
+}
+
+// LaunchTemplateReference references an ECS launch template by ID or name.
+type LaunchTemplateReference struct {
+	// 启动模板ID。您必须指定LaunchTemplateId或LaunchTemplateName以确定启动模板。
+	// +optional
+	ID string `json:"id,omitempty"`
+	// 启动模板名称。您必须指定LaunchTemplateId或LaunchTemplateName以确定启动模板。
+	// +optional
+	Name string `json:"name,omitempty"`
+	// 启动模板的版本。如果不指定启动模板版本号，则采用默认版本。
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Version *int64 `json:"version,omitempty"`
+}
+
+// ImageLookup describes the images an ACKMachine may be created from, the newest one
+// matching all given criteria is used.
+type ImageLookup struct {
+	// 镜像族系名称，查询镜像族系中最新可用的镜像。
+	// +optional
+	ImageFamily string `json:"imageFamily,omitempty"`
+	// OSNamePattern is a shell pattern matched against the OS name of the image, e.g. "CentOS 7.* 64*".
+	// +optional
+	OSNamePattern string `json:"osNamePattern,omitempty"`
+	// 镜像来源。取值范围：system：阿里云提供的公共镜像。self：您创建的自定义镜像。others：其他阿里云用户共享给您的镜像。marketplace：镜像市场提供的镜像。
+	// +kubebuilder:validation:Enum=system;self;others;marketplace
+	// +optional
+	ImageOwnerAlias string `json:"imageOwnerAlias,omitempty"`
+	// KubernetesVersion is matched against the kubernetes-version tag of the image.
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+}
+
+// LaunchTemplateStatus is the launch template version the instance has been created from.
+type LaunchTemplateStatus struct {
+	// +optional
+	ID string `json:"id,omitempty"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	Version int64 `json:"version,omitempty"`
+}
+
+// EIPSpec defines the elastic IP address of an ACKMachine.
+type EIPSpec struct {
+	// 已有弹性公网IP的ID。不指定时为实例申请新的弹性公网IP，新申请的弹性公网IP在删除ACKMachine时释放。
+	// +optional
+	AllocationId string `json:"allocationId,omitempty"`
+	// 新申请的弹性公网IP的带宽峰值，单位为Mbit/s，默认值为5。
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Bandwidth int64 `json:"bandwidth,omitempty"`
+	// 新申请的弹性公网IP的计量方式。取值范围：PayByBandwidth：按带宽计费。PayByTraffic（默认）：按流量计费。
+	// +kubebuilder:validation:Enum=PayByBandwidth;PayByTraffic
+	// +optional
+	InternetChargeType string `json:"internetChargeType,omitempty"`
+}
+
+// EIPStatus is the elastic IP address associated with the instance.
+type EIPStatus struct {
+	// +optional
+	AllocationId string `json:"allocationId,omitempty"`
+	// +optional
+	IpAddress string `json:"ipAddress,omitempty"`
+	// Allocated is true if the elastic IP address has been allocated by the provider
+	// and is released with the ACKMachine.
+	// +optional
+	Allocated bool `json:"allocated,omitempty"`
+}
+
+// SpotOptions defines the bidding options of a preemptible instance.
+type SpotOptions struct {
+	// 按量实例的抢占策略。取值范围：SpotWithPriceLimit：设置上限价格的抢占式实例。SpotAsPriceGo：系统自动出价，跟随当前市场实际价格。
+	// +kubebuilder:validation:Enum=SpotWithPriceLimit;SpotAsPriceGo
+	SpotStrategy SpotStrategy `json:"spotStrategy"`
+	// 设置实例的每小时最高价格，支持最大3位小数，仅在SpotStrategy取值为SpotWithPriceLimit时生效。
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]{1,3})?$`
+	// +optional
+	MaxPrice *string `json:"maxPrice,omitempty"`
+	// 抢占式实例的保留时长，单位为小时。取值范围：0~6，取值为0时不保证实例运行时长。
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=6
+	// +optional
+	SpotDuration *int64 `json:"spotDuration,omitempty"`
+}
+
+type CpuOptions struct {
+	// CPU核心数。该参数不支持自定义设置，只能采用默认值。
+	// +optional
+	Core int64 `json:"core,omitempty"`
+	// +optional
+	ThreadsPerCore int64 `json:"threadsPerCore,omitempty"`
+}
+
+type MachineNetworkSpec struct {
+	// SecurityGroupId决定了实例的网络类型，例如，如果指定安全组的网络类型为专有网络VPC，实例则为VPC类型，并同时需要指定参数VSwitchId。
+	//如果您不指定LaunchTemplateId或LaunchTemplateName以确定启动模板，SecurityGroupId为必选参数。
+	// +optional
+	SecurityGroupId string `json:"securityGroupId,omitempty"`
+	// +optional
+	VSwitchId string `json:"vSwitchId,omitempty"`
+	// 公网入带宽最大值，单位为Mbit/s。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InternetMaxBandwidthIn int64 `json:"internetMaxBandwidthIn,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InternetMaxBandwidthOut int64 `json:"internetMaxBandwidthOut,omitempty"`
+	// 网络计费类型
+	// 取值范围：PayByBandwidth：按固定带宽计费。 PayByTraffic（默认）：按使用流量计费。
+	// +kubebuilder:validation:Enum=PayByBandwidth;PayByTraffic
+	// +optional
+	InternetChargeType string `json:"internetChargeType,omitempty"`
+	// +optional
+	PrivateIpAddress string `json:"privateIpAddress,omitempty"`
+}
+
+type MachineVolumeSpec struct {
+	// +optional
+	SystemDisk SystemDisk `json:"systemDisk,omitempty"`
+	// 数据盘，最多16块。
+	// +kubebuilder:validation:MaxItems=16
+	// +optional
+	DataDisks []*DataDisk `json:"dataDisks,omitempty"`
+	// 公网入带宽最大值，单位为Mbit/s。
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InternetMaxBandwidthIn int64 `json:"internetMaxBandwidthIn,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InternetMaxBandwidthOut int64 `json:"internetMaxBandwidthOut,omitempty"`
+}
+
+type UserData struct {
+	// +optional
+	Encryped *bool `json:"encryped,omitempty"`
+	// +optional
+	Datas string `json:"datas,omitempty"`
+}
+
+// ACKMachineStatus defines the observed state of ACKMachine
+type ACKMachineStatus struct {
+	// Ready is true when the provider resource is ready.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// +optional
+	MachineId string `json:"machineId,omitempty"`
+	// +optional
+	InstanceId string `json:"instanceId,omitempty"`
+	// InstanceState is the state of the ECS instance for this machine.
+	// +optional
+	InstanceState *InstanceState `json:"instanceState,omitempty"`
+	// ImageId is the image resolved from the image lookup or reference.
+	// +optional
+	ImageId string `json:"imageId,omitempty"`
+	// InstanceType is the instance type chosen from the acceptable instance types.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+	// ZoneId is the zone the instance has been created in, or the zone chosen for the instance
+	// until it has been created.
+	// +optional
+	ZoneId string `json:"zoneId,omitempty"`
+	// VSwitchId is the vswitch the instance has been created in, or the vswitch chosen for the
+	// instance until it has been created.
+	// +optional
+	VSwitchId string `json:"vSwitchId,omitempty"`
+	// NoStockZones are the zones creating the instance failed in for lack of stock, they are
+	// skipped when the zone is chosen again.
+	// +optional
+	NoStockZones []string `json:"noStockZones,omitempty"`
+	// Addresses contains the ECS instance associated addresses.
+	// +optional
+	Addresses []clusterv1.MachineAddress `json:"addresses,omitempty"`
+	// EIP is the elastic IP address associated with the instance.
+	// +optional
+	EIP *EIPStatus `json:"eip,omitempty"`
+	// NetworkInterfaces are the secondary elastic network interfaces of the instance,
+	// in the order of the spec.
+	// +optional
+	NetworkInterfaces []NetworkInterfaceStatus `json:"networkInterfaces,omitempty"`
+	// Volumes are the system and data disks attached to the instance.
+	// +optional
+	Volumes []Volume `json:"volumes,omitempty"`
+	// LaunchTemplate is the resolved launch template version the instance has been created from.
+	// +optional
+	LaunchTemplate *LaunchTemplateStatus `json:"launchTemplate,omitempty"`
+	// ExpiredTime is the expiry time of a PrePaid instance.
+	// +optional
+	ExpiredTime *metav1.Time `json:"expiredTime,omitempty"`
+
+	// +optional
+	FailureReason *errors.MachineStatusError `json:"failureReason,omitempty"`
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
+	// Conditions defines current service state of the ACKMachine.
+	// +optional
+	Conditions Conditions `json:"conditions,omitempty"`
+}
+
+// NetworkInterface is a secondary elastic network interface attached to the instance.
+type NetworkInterface struct {
+	// 已有辅助弹性网卡的ID。指定时挂载已有的弹性网卡，删除ACKMachine时不会删除该网卡，其余字段不可设置。
+	// +optional
+	ID string `json:"id,omitempty"`
+	// 辅助弹性网卡名称
+	// +optional
+	NetworkInterfaceName string `json:"networkInterfaceName,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+	// 辅助弹性网卡的主IP地址。
+	//默认值：从网卡所属的交换机网段中随机选择一个IP地址。
+	// +optional
+	PrimaryIpAddress string `json:"primaryIpAddress,omitempty"`
+	//辅助弹性网卡所属的虚拟交换机ID。
+	//默认值：ECS实例所属的虚拟交换机。
+	// +optional
+	VSwitchId string `json:"vSwitchId,omitempty"`
+	// 辅助弹性网卡所属的安全组，与SecurityGroupIds互斥。
+	//默认值：ECS实例所属的安全组。
+	// +optional
+	SecurityGroupId string `json:"securityGroupId,omitempty"`
+	// +optional
+	SecurityGroupIds []string `json:"securityGroupIds,omitempty"`
+}
+
+// NetworkInterfaceStatus is a secondary elastic network interface of the instance.
+type NetworkInterfaceStatus struct {
+	// 弹性网卡ID
+	ID string `json:"id"`
+	// +optional
+	Name string `json:"name,omitempty"`
+	// +optional
+	PrivateIpAddress string `json:"privateIpAddress,omitempty"`
+	// +optional
+	MacAddress string `json:"macAddress,omitempty"`
+	// 弹性网卡挂载的实例ID
+	// +optional
+	InstanceId string `json:"instanceId,omitempty"`
+	// Created is true if the network interface has been created by the provider
+	// and is deleted with the ACKMachine.
+	// +optional
+	Created bool `json:"created,omitempty"`
+}
+
+type SystemDisk struct {
+	// +kubebuilder:validation:Pattern=`^[0-9]+$`
+	// +optional
+	Size string `json:"size,omitempty"`
+	// +kubebuilder:validation:Enum=cloud;cloud_efficiency;cloud_ssd;cloud_essd
+	// +optional
+	Category string `json:"category,omitempty"`
+	// +optional
+	DiskName string `json:"diskName,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+	// ESSD云盘的性能等级，仅当Category取值为cloud_essd时生效。取值范围：PL0，PL1（默认），PL2，PL3。
+	// +kubebuilder:validation:Enum=PL0;PL1;PL2;PL3
+	// +optional
+	PerformanceLevel string `json:"performanceLevel,omitempty"`
+	// +optional
+	AutoSnapshotPolicyId string `json:"autoSnapshotPolicyId,omitempty"`
+}
+
+type DataDisk struct {
+	// +kubebuilder:validation:Pattern=`^[0-9]+$`
+	// +optional
+	Size string `json:"size,omitempty"`
+	// 创建数据盘使用的快照。指定快照时Size可以为空，此时数据盘大小为快照大小。
+	// +optional
+	SnapshotId string `json:"snapshotId,omitempty"`
+	// +kubebuilder:validation:Enum=cloud;cloud_efficiency;cloud_ssd;cloud_essd
+	// +optional
+	Category string `json:"category,omitempty"`
+	// +optional
+	Encrypted *bool `json:"encrypted,omitempty"`
+	// 数据盘使用的KMS密钥ID，仅当Encrypted为true时生效。
+	// +optional
+	KMSKeyId string `json:"kmsKeyId,omitempty"`
+	// +optional
+	DiskName string `json:"diskName,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+	// +optional
+	DeleteWithInstance *bool `json:"deleteWithInstance,omitempty"`
+	// ESSD云盘的性能等级，仅当Category取值为cloud_essd时生效。取值范围：PL0，PL1（默认），PL2，PL3。
+	// +kubebuilder:validation:Enum=PL0;PL1;PL2;PL3
+	// +optional
+	PerformanceLevel string `json:"performanceLevel,omitempty"`
+	// +optional
+	AutoSnapshotPolicyId string `json:"autoSnapshotPolicyId,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=ackmachines,scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+
+// ACKMachine is the Schema for the ackmachines API
+type ACKMachine struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ACKMachineSpec   `json:"spec,omitempty"`
+	Status ACKMachineStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ACKMachineList contains a list of ACKMachine
+type ACKMachineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ACKMachine `json:"items"`
+}
+
+func (am *ACKMachine) HasFailed() bool {
+	return am.Status.FailureReason != nil || am.Status.FailureMessage != nil
+}
+
+// GetConditions returns the observations of the operational state of the ACKMachine resource.
+func (am *ACKMachine) GetConditions() Conditions {
+	return am.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the ACKMachine to the predescribed Conditions.
+func (am *ACKMachine) SetConditions(conditions Conditions) {
+	am.Status.Conditions = conditions
+}
+
+func init() {
+	SchemeBuilder.Register(&ACKMachine{}, &ACKMachineList{})
+}
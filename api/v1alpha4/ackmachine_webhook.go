@@ -0,0 +1,479 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+func (r *ACKMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-ack-cluster-k8s-io-v1alpha4-ackmachine,mutating=false,failurePolicy=fail,groups=ack.cluster.k8s.io,resources=ackmachines,versions=v1alpha4,name=validation.ackmachine.ack.cluster.k8s.io
+
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-ack-cluster-k8s-io-v1alpha4-ackmachine,mutating=true,failurePolicy=fail,groups=ack.cluster.k8s.io,resources=ackmachines,versions=v1alpha4,name=default.ackmachine.ack.cluster.k8s.io
+
+// DefaultEIPBandwidth is the bandwidth in Mbit/s of the elastic IP addresses allocated for ACKMachines.
+const DefaultEIPBandwidth = 5
+
+var _ webhook.Validator = &ACKMachine{}
+var _ webhook.Defaulter = &ACKMachine{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type.
+// The RegionId is inherited from the ACKCluster by the ACKMachine controller.
+// The instance settings are not defaulted for ACKMachines created from a launch template,
+// any value set here would override the one of the template.
+func (r *ACKMachine) Default() {
+	if eip := r.Spec.EIP; eip != nil && eip.AllocationId == "" {
+		if eip.Bandwidth == 0 {
+			eip.Bandwidth = DefaultEIPBandwidth
+		}
+		if eip.InternetChargeType == "" {
+			eip.InternetChargeType = string(InternetChargeTypePayByTraffic)
+		}
+	}
+
+	if r.Spec.InstanceChargeType == string(InstanceChargeTypePrePaid) && r.Spec.PeriodUnit == "" {
+		r.Spec.PeriodUnit = string(PeriodUnitMonth)
+	}
+
+	if r.Spec.LaunchTemplate != nil {
+		return
+	}
+
+	if r.Spec.MachineNetworkSpec.InternetChargeType == "" {
+		r.Spec.MachineNetworkSpec.InternetChargeType = string(InternetChargeTypePayByTraffic)
+	}
+
+	volume := &r.Spec.MachineVolumeSpec
+	if volume.SystemDisk.Category == "" {
+		volume.SystemDisk.Category = string(DiskCategoryCloudEfficiency)
+	}
+	for _, disk := range volume.DataDisks {
+		if disk != nil && disk.Category == "" {
+			disk.Category = string(DiskCategoryCloudEfficiency)
+		}
+	}
+}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ACKMachine) ValidateCreate() error {
+	return r.validate(nil)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ACKMachine) ValidateUpdate(old runtime.Object) error {
+	oldM, ok := old.(*ACKMachine)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected an ACKMachine but got a %T", old))
+	}
+	// the old object may have been stored before the defaulting webhook was deployed, the
+	// fields it defaults are compared as if it had been defaulted already
+	defaulted := oldM.DeepCopy()
+	defaulted.Default()
+	return r.validate(defaulted)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ACKMachine) ValidateDelete() error {
+	return nil
+}
+
+func (r *ACKMachine) validate(old *ACKMachine) error {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validateMachineVolumeSpec(&r.Spec.MachineVolumeSpec, specPath.Child("machineVolumeSpec"))...)
+	allErrs = append(allErrs, validateSpotOptions(r.Spec.SpotOptions, specPath.Child("spotOptions"))...)
+	allErrs = append(allErrs, validateChargeType(&r.Spec, specPath)...)
+	allErrs = append(allErrs, validateLaunchTemplate(&r.Spec, specPath)...)
+	allErrs = append(allErrs, validateImage(&r.Spec, specPath)...)
+	allErrs = append(allErrs, validateEIP(r.Spec.EIP, specPath.Child("eip"))...)
+	allErrs = append(allErrs, validateNetworkInterfaces(r.Spec.NetworkInterfaces, specPath.Child("networkInterfaces"))...)
+
+	if old != nil {
+		immutables := []struct {
+			name     string
+			old, new string
+		}{
+			{name: "imageId", old: old.Spec.ImageId, new: r.Spec.ImageId},
+			{name: "instanceType", old: old.Spec.InstanceType, new: r.Spec.InstanceType},
+			{name: "instanceChargeType", old: old.Spec.InstanceChargeType, new: r.Spec.InstanceChargeType},
+			{name: "periodUnit", old: old.Spec.PeriodUnit, new: r.Spec.PeriodUnit},
+			{name: "resourceGroupId", old: old.Spec.ResourceGroupId, new: r.Spec.ResourceGroupId},
+		}
+		// regionId may be inherited from the ACKCluster once, zoneId and vSwitchId may be
+		// placed by the controller once, after that they are immutable
+		setOnce := []struct {
+			path     *field.Path
+			old, new string
+		}{
+			{path: specPath.Child("regionId"), old: old.Spec.RegionId, new: r.Spec.RegionId},
+			{path: specPath.Child("zoneId"), old: old.Spec.ZoneId, new: r.Spec.ZoneId},
+			{path: specPath.Child("machineNetworkSpec", "vSwitchId"), old: old.Spec.MachineNetworkSpec.VSwitchId, new: r.Spec.MachineNetworkSpec.VSwitchId},
+		}
+		for _, f := range setOnce {
+			if f.old != "" && f.old != f.new {
+				allErrs = append(allErrs, field.Forbidden(f.path, "field is immutable"))
+			}
+		}
+		for _, f := range immutables {
+			if f.old != f.new {
+				allErrs = append(allErrs, field.Forbidden(specPath.Child(f.name), "field is immutable"))
+			}
+		}
+		oldNetwork, newNetwork := old.Spec.MachineNetworkSpec, r.Spec.MachineNetworkSpec
+		oldNetwork.VSwitchId, newNetwork.VSwitchId = "", ""
+		if !reflect.DeepEqual(oldNetwork, newNetwork) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("machineNetworkSpec"), "field is immutable"))
+		}
+		if old.Spec.Period != r.Spec.Period {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("period"), "field is immutable"))
+		}
+		allErrs = append(allErrs, validateMachineVolumeSpecUpdate(&old.Spec.MachineVolumeSpec, &r.Spec.MachineVolumeSpec, specPath.Child("machineVolumeSpec"))...)
+		if !reflect.DeepEqual(old.Spec.ImageRef, r.Spec.ImageRef) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("imageRef"), "field is immutable"))
+		}
+		if !reflect.DeepEqual(old.Spec.ImageLookup, r.Spec.ImageLookup) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("imageLookup"), "field is immutable"))
+		}
+		if !reflect.DeepEqual(old.Spec.InstanceTypes, r.Spec.InstanceTypes) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("instanceTypes"), "field is immutable"))
+		}
+		if !reflect.DeepEqual(old.Spec.ZoneIds, r.Spec.ZoneIds) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("zoneIds"), "field is immutable"))
+		}
+		if !reflect.DeepEqual(old.Spec.LaunchTemplate, r.Spec.LaunchTemplate) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("launchTemplate"), "field is immutable"))
+		}
+		if !reflect.DeepEqual(old.Spec.NetworkInterfaces, r.Spec.NetworkInterfaces) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("networkInterfaces"), "field is immutable"))
+		}
+		if !reflect.DeepEqual(old.Spec.EIP, r.Spec.EIP) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("eip"), "field is immutable"))
+		}
+		if !reflect.DeepEqual(old.Spec.SpotOptions, r.Spec.SpotOptions) {
+			allErrs = append(allErrs, field.Forbidden(specPath.Child("spotOptions"), "field is immutable"))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("ACKMachine").GroupKind(), r.Name, allErrs)
+}
+
+func validateMachineVolumeSpec(spec *MachineVolumeSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	systemDiskPath := fldPath.Child("systemDisk")
+	allErrs = append(allErrs, validateDiskCategory(spec.SystemDisk.Category, systemDiskPath.Child("category"))...)
+	allErrs = append(allErrs, validateSystemDiskSize(spec.SystemDisk.Size, systemDiskPath.Child("size"))...)
+	// an invalid size has been reported above, only the performance level is checked then
+	systemDiskSize, _ := strconv.ParseInt(spec.SystemDisk.Size, 10, 64)
+	allErrs = append(allErrs, validatePerformanceLevel(spec.SystemDisk.Category, spec.SystemDisk.PerformanceLevel, systemDiskSize, systemDiskPath.Child("performanceLevel"))...)
+
+	for i, disk := range spec.DataDisks {
+		if disk == nil {
+			continue
+		}
+		diskPath := fldPath.Child("dataDisks").Index(i)
+		allErrs = append(allErrs, validateDataDisk(disk, diskPath)...)
+	}
+
+	return allErrs
+}
+
+func validateDataDisk(disk *DataDisk, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateDiskCategory(disk.Category, fldPath.Child("category"))...)
+
+	var size int64
+	if disk.Size == "" {
+		// the disk size falls back to the snapshot size
+		if disk.SnapshotId == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("size"), "size is required unless the disk is created from a snapshot"))
+		}
+	} else {
+		value, err := strconv.ParseInt(disk.Size, 10, 64)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), disk.Size, "must be an integer in GiB"))
+		} else {
+			size = value
+			allErrs = append(allErrs, validateDataDiskSize(disk.Category, size, fldPath.Child("size"))...)
+		}
+	}
+	allErrs = append(allErrs, validatePerformanceLevel(disk.Category, disk.PerformanceLevel, size, fldPath.Child("performanceLevel"))...)
+
+	encrypted := disk.Encrypted != nil && *disk.Encrypted
+	if encrypted && DiskCategory(disk.Category) == DiskCategoryCloud {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("encrypted"), "cloud disks do not support encryption"))
+	}
+	if disk.KMSKeyId != "" && !encrypted {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("kmsKeyId"), "kmsKeyId requires encrypted to be true"))
+	}
+
+	return allErrs
+}
+
+func validateSpotOptions(spot *SpotOptions, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if spot == nil {
+		return allErrs
+	}
+
+	switch spot.SpotStrategy {
+	case SpotStrategySpotWithPriceLimit, SpotStrategySpotAsPriceGo:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("spotStrategy"), spot.SpotStrategy,
+			[]string{string(SpotStrategySpotWithPriceLimit), string(SpotStrategySpotAsPriceGo)}))
+	}
+
+	if spot.MaxPrice != nil {
+		if spot.SpotStrategy != SpotStrategySpotWithPriceLimit {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("maxPrice"),
+				fmt.Sprintf("maxPrice is only supported with spotStrategy %s", SpotStrategySpotWithPriceLimit)))
+		} else if price, err := strconv.ParseFloat(*spot.MaxPrice, 64); err != nil || price <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPrice"), *spot.MaxPrice, "must be a positive price per hour"))
+		}
+	}
+
+	if spot.SpotDuration != nil && (*spot.SpotDuration < 0 || *spot.SpotDuration > 6) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("spotDuration"), *spot.SpotDuration, "must be between 0 and 6 hours"))
+	}
+
+	return allErrs
+}
+
+func validateChargeType(spec *ACKMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.InstanceChargeType != string(InstanceChargeTypePrePaid) {
+		// the subscription settings only take effect for PrePaid instances
+		if spec.Period != 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("period"), "period is only supported with instanceChargeType PrePaid"))
+		}
+		if spec.PeriodUnit != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("periodUnit"), "periodUnit is only supported with instanceChargeType PrePaid"))
+		}
+		if spec.AutoRenew || spec.AutoRenewPeriod != 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("autoRenew"), "auto renew is only supported with instanceChargeType PrePaid"))
+		}
+		return allErrs
+	}
+
+	if spec.SpotOptions != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("spotOptions"), "spot instances must be PostPaid"))
+	}
+
+	unit := PeriodUnit(spec.PeriodUnit)
+	if unit == "" {
+		unit = PeriodUnitMonth
+	}
+	allowedPeriods, ok := periods[unit]
+	if !ok {
+		return append(allErrs, field.NotSupported(fldPath.Child("periodUnit"), spec.PeriodUnit,
+			[]string{string(PeriodUnitWeek), string(PeriodUnitMonth)}))
+	}
+
+	if spec.Period == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("period"), "period is required with instanceChargeType PrePaid"))
+	} else {
+		allErrs = append(allErrs, validatePeriod(spec.Period, allowedPeriods, fldPath.Child("period"))...)
+	}
+
+	if spec.AutoRenew {
+		if spec.AutoRenewPeriod == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("autoRenewPeriod"), "autoRenewPeriod is required when autoRenew is true"))
+		} else {
+			allErrs = append(allErrs, validatePeriod(spec.AutoRenewPeriod, autoRenewPeriods[unit], fldPath.Child("autoRenewPeriod"))...)
+		}
+	} else if spec.AutoRenewPeriod != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("autoRenewPeriod"), "autoRenewPeriod is only supported when autoRenew is true"))
+	}
+
+	return allErrs
+}
+
+func validateLaunchTemplate(spec *ACKMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	template := spec.LaunchTemplate
+	if template == nil {
+		// without a launch template the instance type and image are required by RunInstances
+		if spec.InstanceType == "" && len(spec.InstanceTypes) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("instanceType"), "one of instanceType or instanceTypes is required without a launchTemplate"))
+		}
+		if spec.ImageId == "" && spec.ImageLookup == nil && spec.ImageRef == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("imageId"), "one of imageId, imageLookup or imageRef is required without a launchTemplate"))
+		}
+		return allErrs
+	}
+
+	templatePath := fldPath.Child("launchTemplate")
+	if template.ID == "" && template.Name == "" {
+		allErrs = append(allErrs, field.Required(templatePath, "one of id or name is required"))
+	}
+	if template.ID != "" && template.Name != "" {
+		allErrs = append(allErrs, field.Forbidden(templatePath.Child("name"), "only one of id or name may be set"))
+	}
+	if template.Version != nil && *template.Version < 1 {
+		allErrs = append(allErrs, field.Invalid(templatePath.Child("version"), *template.Version, "must be greater than 0"))
+	}
+
+	return allErrs
+}
+
+// validateMachineVolumeSpecUpdate only allows to grow the existing disks, the disks are resized online.
+func validateMachineVolumeSpecUpdate(old, new *MachineVolumeSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	systemDiskPath := fldPath.Child("systemDisk")
+	oldSystemDisk, newSystemDisk := old.SystemDisk, new.SystemDisk
+	oldSystemDisk.Size, newSystemDisk.Size = "", ""
+	if oldSystemDisk != newSystemDisk {
+		allErrs = append(allErrs, field.Forbidden(systemDiskPath, "only the size of the system disk may be changed"))
+	}
+	allErrs = append(allErrs, validateDiskSizeIncrease(old.SystemDisk.Size, new.SystemDisk.Size, systemDiskPath.Child("size"))...)
+
+	if len(old.DataDisks) != len(new.DataDisks) {
+		return append(allErrs, field.Forbidden(fldPath.Child("dataDisks"), "data disks can not be added or removed"))
+	}
+	for i := range new.DataDisks {
+		oldDisk, newDisk := old.DataDisks[i], new.DataDisks[i]
+		if oldDisk == nil || newDisk == nil {
+			if oldDisk != newDisk {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Child("dataDisks").Index(i), "data disks can not be added or removed"))
+			}
+			continue
+		}
+		diskPath := fldPath.Child("dataDisks").Index(i)
+		oldCopy, newCopy := oldDisk.DeepCopy(), newDisk.DeepCopy()
+		oldCopy.Size, newCopy.Size = "", ""
+		if !reflect.DeepEqual(oldCopy, newCopy) {
+			allErrs = append(allErrs, field.Forbidden(diskPath, "only the size of a data disk may be changed"))
+		}
+		allErrs = append(allErrs, validateDiskSizeIncrease(oldDisk.Size, newDisk.Size, diskPath.Child("size"))...)
+	}
+
+	return allErrs
+}
+
+func validateDiskSizeIncrease(old, new string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if old == new || old == "" {
+		return allErrs
+	}
+	oldSize, err := strconv.ParseInt(old, 10, 64)
+	if err != nil {
+		return allErrs
+	}
+	newSize, err := strconv.ParseInt(new, 10, 64)
+	if err != nil || newSize < oldSize {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("disks can not be shrunk, size must be at least %s GiB", old)))
+	}
+	return allErrs
+}
+
+func validateEIP(eip *EIPSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if eip == nil || eip.AllocationId == "" {
+		return allErrs
+	}
+
+	// the bandwidth and charge type of an existing elastic IP address are managed outside of the ACKMachine
+	if eip.Bandwidth != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("bandwidth"), "bandwidth can not be set for an existing EIP"))
+	}
+	if eip.InternetChargeType != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("internetChargeType"), "internetChargeType can not be set for an existing EIP"))
+	}
+	return allErrs
+}
+
+func validateNetworkInterfaces(nics []NetworkInterface, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	ids := map[string]bool{}
+	for i, nic := range nics {
+		nicPath := fldPath.Index(i)
+		if nic.ID != "" {
+			if ids[nic.ID] {
+				allErrs = append(allErrs, field.Duplicate(nicPath.Child("id"), nic.ID))
+			}
+			ids[nic.ID] = true
+
+			// an existing network interface is attached as it is
+			existing := NetworkInterface{ID: nic.ID}
+			if !reflect.DeepEqual(nic, existing) {
+				allErrs = append(allErrs, field.Forbidden(nicPath, "only id may be set for an existing network interface"))
+			}
+			continue
+		}
+		if nic.SecurityGroupId != "" && len(nic.SecurityGroupIds) > 0 {
+			allErrs = append(allErrs, field.Forbidden(nicPath.Child("securityGroupIds"), "securityGroupId and securityGroupIds are mutually exclusive"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateImage(spec *ACKMachineSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	sources := 0
+	for _, set := range []bool{spec.ImageId != "", spec.ImageLookup != nil, spec.ImageRef != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("imageId"), "only one of imageId, imageLookup or imageRef may be set"))
+	}
+	if spec.ImageRef != nil && spec.ImageRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("imageRef", "name"), "name is required"))
+	}
+
+	lookup := spec.ImageLookup
+	if lookup == nil {
+		return allErrs
+	}
+
+	lookupPath := fldPath.Child("imageLookup")
+	if lookup.ImageFamily == "" && lookup.OSNamePattern == "" && lookup.KubernetesVersion == "" {
+		allErrs = append(allErrs, field.Required(lookupPath, "one of imageFamily, osNamePattern or kubernetesVersion is required"))
+	}
+	if lookup.OSNamePattern != "" {
+		if _, err := path.Match(lookup.OSNamePattern, ""); err != nil {
+			allErrs = append(allErrs, field.Invalid(lookupPath.Child("osNamePattern"), lookup.OSNamePattern, err.Error()))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestACKMachineValidateUpdateUndefaulted(t *testing.T) {
+	g := NewWithT(t)
+
+	// an ACKMachine stored before the defaulting webhook was deployed
+	old := &ACKMachine{
+		Spec: ACKMachineSpec{
+			InstanceType: "ecs.g6.large",
+			ImageId:      "centos_7",
+			EIP:          &EIPSpec{},
+			MachineVolumeSpec: MachineVolumeSpec{
+				SystemDisk: SystemDisk{Size: "40"},
+				DataDisks:  []*DataDisk{{Size: "100"}},
+			},
+		},
+	}
+	// the defaulting webhook runs before the validating webhook on the next update
+	updated := old.DeepCopy()
+	updated.Default()
+	updated.Finalizers = []string{"ackmachine.ack.cluster.k8s.io"}
+
+	g.Expect(updated.ValidateUpdate(old)).To(Succeed())
+
+	// the defaulted fields stay immutable
+	changed := updated.DeepCopy()
+	changed.Spec.MachineVolumeSpec.SystemDisk.Category = string(DiskCategoryCloudSSD)
+	g.Expect(changed.ValidateUpdate(old)).NotTo(Succeed())
+}
+
+func TestACKMachineValidateUpdate(t *testing.T) {
+	old := &ACKMachine{
+		Spec: ACKMachineSpec{
+			RegionId:        "cn-hangzhou",
+			ResourceGroupId: "rg-1",
+			InstanceType:    "ecs.g6.large",
+			ImageId:         "centos_7",
+			MachineNetworkSpec: MachineNetworkSpec{
+				SecurityGroupId: "sg-1",
+			},
+			MachineVolumeSpec: MachineVolumeSpec{
+				SystemDisk: SystemDisk{Size: "40"},
+				DataDisks:  []*DataDisk{{Size: "100"}},
+			},
+		},
+	}
+	old.Default()
+
+	tests := []struct {
+		name   string
+		update func(m *ACKMachine)
+		valid  bool
+	}{
+		{name: "unchanged", update: func(m *ACKMachine) {}, valid: true},
+		{name: "zone placed", update: func(m *ACKMachine) { m.Spec.ZoneId = "cn-hangzhou-h" }, valid: true},
+		{name: "vswitch placed", update: func(m *ACKMachine) { m.Spec.MachineNetworkSpec.VSwitchId = "vsw-1" }, valid: true},
+		{name: "system disk grown", update: func(m *ACKMachine) { m.Spec.MachineVolumeSpec.SystemDisk.Size = "80" }, valid: true},
+		{name: "data disk grown", update: func(m *ACKMachine) { m.Spec.MachineVolumeSpec.DataDisks[0].Size = "200" }, valid: true},
+		{name: "region changed", update: func(m *ACKMachine) { m.Spec.RegionId = "cn-beijing" }},
+		{name: "resource group changed", update: func(m *ACKMachine) { m.Spec.ResourceGroupId = "rg-2" }},
+		{name: "instance type changed", update: func(m *ACKMachine) { m.Spec.InstanceType = "ecs.g6.xlarge" }},
+		{name: "image changed", update: func(m *ACKMachine) { m.Spec.ImageId = "ubuntu_18" }},
+		{name: "instance types changed", update: func(m *ACKMachine) { m.Spec.InstanceTypes = []string{"ecs.g6.xlarge"} }},
+		{name: "zones changed", update: func(m *ACKMachine) { m.Spec.ZoneIds = []string{"cn-hangzhou-h"} }},
+		{name: "security group changed", update: func(m *ACKMachine) { m.Spec.MachineNetworkSpec.SecurityGroupId = "sg-2" }},
+		{name: "system disk shrunk", update: func(m *ACKMachine) { m.Spec.MachineVolumeSpec.SystemDisk.Size = "20" }},
+		{name: "system disk category changed", update: func(m *ACKMachine) {
+			m.Spec.MachineVolumeSpec.SystemDisk.Category = string(DiskCategoryCloudSSD)
+		}},
+		{name: "data disk shrunk", update: func(m *ACKMachine) { m.Spec.MachineVolumeSpec.DataDisks[0].Size = "50" }},
+		{name: "data disk category changed", update: func(m *ACKMachine) {
+			m.Spec.MachineVolumeSpec.DataDisks[0].Category = string(DiskCategoryCloudSSD)
+		}},
+		{name: "data disk added", update: func(m *ACKMachine) {
+			m.Spec.MachineVolumeSpec.DataDisks = append(m.Spec.MachineVolumeSpec.DataDisks, &DataDisk{Size: "100", Category: string(DiskCategoryCloudEfficiency)})
+		}},
+		{name: "eip added", update: func(m *ACKMachine) { m.Spec.EIP = &EIPSpec{AllocationId: "eip-1"} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			updated := old.DeepCopy()
+			tt.update(updated)
+			if tt.valid {
+				g.Expect(updated.ValidateUpdate(old)).To(Succeed())
+			} else {
+				g.Expect(updated.ValidateUpdate(old)).NotTo(Succeed())
+			}
+		})
+	}
+}
+
+func TestACKMachineValidateUpdateSetOnce(t *testing.T) {
+	old := &ACKMachine{
+		Spec: ACKMachineSpec{
+			RegionId:     "cn-hangzhou",
+			ZoneId:       "cn-hangzhou-h",
+			InstanceType: "ecs.g6.large",
+			ImageId:      "centos_7",
+			MachineNetworkSpec: MachineNetworkSpec{
+				VSwitchId: "vsw-1",
+			},
+		},
+	}
+	old.Default()
+
+	tests := []struct {
+		name   string
+		update func(m *ACKMachine)
+	}{
+		{name: "zone changed", update: func(m *ACKMachine) { m.Spec.ZoneId = "cn-hangzhou-i" }},
+		{name: "zone cleared", update: func(m *ACKMachine) { m.Spec.ZoneId = "" }},
+		{name: "vswitch changed", update: func(m *ACKMachine) { m.Spec.MachineNetworkSpec.VSwitchId = "vsw-2" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			updated := old.DeepCopy()
+			tt.update(updated)
+			g.Expect(updated.ValidateUpdate(old)).NotTo(Succeed())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+// Conditions and condition Reasons for the ACKMachine object
+
+const (
+	// InstanceDeletableCondition reports whether the ECS instance of the ACKMachine can be released.
+	// PrePaid instances have to be converted to PostPaid before they can be deleted.
+	InstanceDeletableCondition ConditionType = "InstanceDeletable"
+
+	// PrePaidConversionRequestedReason used when the conversion of a PrePaid instance to PostPaid has been
+	// requested, the charge type of the instance is polled until the conversion has been observed.
+	PrePaidConversionRequestedReason = "PrePaidConversionRequested"
+
+	// PrePaidConversionFailedReason used when a PrePaid instance could not be converted to PostPaid,
+	// the instance is kept until the conversion succeeds or the instance is released manually.
+	PrePaidConversionFailedReason = "PrePaidConversionFailed"
+)
+
+const (
+	// VolumesResizedCondition reports whether the disks of the instance have the size of the spec.
+	// It is False with FilesystemResizePendingReason after a disk has been resized online, until the
+	// FilesystemResizePendingAnnotation is removed. The provider can not observe the filesystems on the node.
+	VolumesResizedCondition ConditionType = "VolumesResized"
+
+	// FilesystemResizePendingReason used when a disk has been resized online and the partition
+	// and filesystem still have to be grown on the node.
+	FilesystemResizePendingReason = "FilesystemResizePending"
+
+	// VolumeResizeNotSupportedReason used when a disk category does not support online resizing.
+	VolumeResizeNotSupportedReason = "VolumeResizeNotSupported"
+
+	// VolumeShrinkNotSupportedReason used when the spec asks for a disk smaller than the existing disk.
+	VolumeShrinkNotSupportedReason = "VolumeShrinkNotSupported"
+
+	// VolumeResizeFailedReason used when resizing a disk failed.
+	VolumeResizeFailedReason = "VolumeResizeFailed"
+)
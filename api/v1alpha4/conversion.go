@@ -0,0 +1,29 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+// Hub marks ACKCluster as a conversion hub.
+func (*ACKCluster) Hub() {}
+
+// Hub marks ACKClusterList as a conversion hub.
+func (*ACKClusterList) Hub() {}
+
+// Hub marks ACKMachine as a conversion hub.
+func (*ACKMachine) Hub() {}
+
+// Hub marks ACKMachineList as a conversion hub.
+func (*ACKMachineList) Hub() {}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"sort"
+	"strings"
+)
+
+// Tags defines a map of tags.
+type Tags map[string]string
+
+// Merge merges in tags from other. If a tag already exists, it is replaced by the tag in other.
+func (t Tags) Merge(other Tags) {
+	for k, v := range other {
+		t[k] = v
+	}
+}
+
+// Difference returns the tags of t which are missing or have another value in other.
+func (t Tags) Difference(other Tags) Tags {
+	res := Tags{}
+	for k, v := range t {
+		if value, ok := other[k]; !ok || value != v {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// Keys returns the sorted keys of the tags.
+func (t Tags) Keys() []string {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ResourceLifecycle configures the lifecycle of a resource.
+type ResourceLifecycle string
+
+const (
+	// ResourceLifecycleOwned is the value we use when tagging resources to indicate
+	// that the resource is considered owned and managed by the cluster,
+	// and in particular that the lifecycle is tied to the lifecycle of the cluster.
+	ResourceLifecycleOwned = ResourceLifecycle("owned")
+
+	// ResourceLifecycleShared is the value we use when tagging resources to indicate
+	// that the resource is shared between multiple clusters, and should not be destroyed
+	// if the cluster is destroyed.
+	ResourceLifecycleShared = ResourceLifecycle("shared")
+
+	// NameAliyunProviderPrefix is the tag prefix we use to differentiate
+	// cluster-api-provider-aliyun owned components from other tooling that
+	// uses NameKubernetesClusterPrefix.
+	NameAliyunProviderPrefix = "sigs.k8s.io/cluster-api-provider-aliyun/"
+
+	// NameAliyunProviderOwned is the tag name we use to differentiate
+	// cluster-api-provider-aliyun owned components from other tooling that
+	// uses NameKubernetesClusterPrefix.
+	NameAliyunProviderOwned = NameAliyunProviderPrefix + "cluster/"
+
+	// LastAppliedTagsAnnotation records the additional tags applied to the resources of
+	// an ACKCluster or ACKMachine, so tags removed from the spec are removed from the resources.
+	LastAppliedTagsAnnotation = "ack.cluster.k8s.io/last-applied-tags"
+)
+
+// ClusterTagKey generates the key for resources associated with a cluster. It is qualified by
+// the namespace, as clusters of the same name may exist in different namespaces.
+func ClusterTagKey(namespace, name string) string {
+	return NameAliyunProviderOwned + namespace + "/" + name
+}
+
+// ParseClusterTagKey returns the namespace and name of the cluster a tag key associates a
+// resource with.
+func ParseClusterTagKey(key string) (namespace, name string, ok bool) {
+	if !strings.HasPrefix(key, NameAliyunProviderOwned) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(key, NameAliyunProviderOwned), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// Build builds the tags of a resource of the cluster from the additional tags and the
+// ownership tag with the given lifecycle.
+func Build(clusterNamespace, clusterName string, lifecycle ResourceLifecycle, additional Tags) Tags {
+	tags := Tags{}
+	tags.Merge(additional)
+	tags[ClusterTagKey(clusterNamespace, clusterName)] = string(lifecycle)
+	return tags
+}
@@ -0,0 +1,252 @@
+package v1alpha4
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+type Instance struct {
+	Id           string `json:"id,omitempty"`
+	InstanceName string `json:"instanceName,omitempty"`
+
+	State InstanceState `json:"state"`
+
+	RegionId string `json:"regionId,omitempty"`
+	ZoneId   string `json:"zoneId,omitempty"`
+
+	//
+	ResourceGroupId string `json:"resourceGroupId,omitempty"`
+	// 实例规格 ecs.g5.large
+	InstanceType string `json:"instanceType,omitempty"`
+	//实例规格族 ecs.g5
+	InstanceTypeFamily string `json:"instanceTypeFamily,omitempty"`
+
+	ImageId string `json:"imageId,omitempty"`
+
+	// The name of the SSH key pair.
+	KeyPairName string `json:"keyPairName,omitempty"`
+
+	SecurityGroupIDs []string `json:"securityGroupIDs,omitempty"`
+
+	//
+	CPU    int64 `json:"cpu,omitempty"`
+	Memory int64 `json:"memory,omitempty"`
+	// 实例的操作系统类型，分为Windows Server和Linux两种。可能值： windows linux
+	OSType string `json:"osType,omitempty"`
+	// 实例的操作系统名称 CentOS 7.4 64 位
+	OSName string `json:"osName,omitempty"`
+	// 实例操作系统的英文名称 CentOS 7.4 64 bit
+	OSNameEn string `json:"osNameEn,omitempty"`
+	// UserData is the raw data script passed to the instance which is run upon bootstrap.
+	// This field must not be base64 encoded and should only be used when running a new instance.
+	UserData *string `json:"userData,omitempty"`
+
+	// Network classic/vpc
+	VlanId              string     `json:"vlanId,omitempty"`
+	InstanceNetworkType string     `json:"instanceNetworkType,omitempty"`
+	InnerIpAddress      []string   `json:"innerIpAddress,omitempty"`
+	PublicIpAddress     []string   `json:"publicIpAddress,omitempty"`
+	EipAddress          EipAddress `json:"eipAddress,omitempty"`
+	// 专有网络VPC属性
+	VpcAttributes VpcAttributes `json:"vpcAttributes,omitempty"`
+
+	// volume related
+	DeviceAvailable *bool `json:"deviceAvailable,omitempty"`
+
+	// 实例的计费方式。可能值：PrePaid：包年包月。PostPaid：按量付费。
+	InstanceChargeType string `json:"instanceChargeType,omitempty"`
+	// 实例的抢占策略。可能值：NoSpot，SpotWithPriceLimit，SpotAsPriceGo。
+	SpotStrategy SpotStrategy `json:"spotStrategy,omitempty"`
+	// 包年包月实例的过期时间。
+	ExpiredTime *metav1.Time `json:"expiredTime,omitempty"`
+	// 实例的锁定原因，例如抢占式实例被回收时为Recycling。
+	LockReasons []string `json:"lockReasons,omitempty"`
+
+	//
+	Tags Tags `json:"tags,omitempty"`
+}
+
+// IsSpot returns true if the instance is a preemptible instance.
+func (i *Instance) IsSpot() bool {
+	return i.SpotStrategy != "" && i.SpotStrategy != SpotStrategyNoSpot
+}
+
+// IsRecycling returns true if the spot instance has been preempted and is being reclaimed.
+func (i *Instance) IsRecycling() bool {
+	for _, reason := range i.LockReasons {
+		if reason == InstanceLockReasonRecycling {
+			return true
+		}
+	}
+	return false
+}
+
+type VpcAttributes struct {
+	// 云产品的IP，用于VPC云产品之间的网络互通。
+	NatIpAddress string `json:"natIpAddress,omitempty"`
+	// 私有IP地址
+	PrivateIpAddress []string `json:"privateIpAddress,omitempty"`
+	// 虚拟交换机ID
+	VSwitchId string `json:"vSwitchId,omitempty"`
+	// 专有网络VPC ID
+	VpcId string `json:"vpcId,omitempty"`
+}
+
+// VSwitch is a vswitch of the cluster network.
+type VSwitch struct {
+	// 虚拟交换机ID
+	ID string `json:"id"`
+	// 虚拟交换机所属的可用区
+	ZoneId string `json:"zoneId"`
+	// +optional
+	CidrBlock string `json:"cidrBlock,omitempty"`
+	// 虚拟交换机中可用的IP地址数量
+	// +optional
+	AvailableIpAddressCount int64 `json:"availableIpAddressCount,omitempty"`
+}
+
+// InstanceState describes the state of an ECS instance.
+type InstanceState string
+
+var (
+	// InstanceStatePending is the string representing an instance in a pending state
+	InstanceStatePending = InstanceState("Pending")
+
+	// InstanceStateStarting is the string representing an instance in a starting state
+	InstanceStateStarting = InstanceState("Starting")
+
+	// InstanceStateRunning is the string representing an instance in a running state
+	InstanceStateRunning = InstanceState("Running")
+
+	// InstanceStateStopping is the string representing an instance in a stopping state
+	InstanceStateStopping = InstanceState("Stopping")
+
+	// InstanceStateStopped is the string representing an instance in a stopped state
+	InstanceStateStopped = InstanceState("Stopped")
+)
+
+// InstanceLockReasonRecycling is the lock reason of a spot instance that is being reclaimed.
+const InstanceLockReasonRecycling = "Recycling"
+
+// EipAddress is the elastic IP address associated with the instance.
+type EipAddress struct {
+	// 弹性公网IP的ID
+	AllocationId string `json:"allocationId,omitempty"`
+	// 弹性公网IP
+	IpAddress string `json:"ipAddress,omitempty"`
+	// 弹性公网IP的公网带宽限速，单位为Mbit/s。
+	Bandwidth int64 `json:"bandwidth,omitempty"`
+	// 弹性公网IP的计费方式。可能值：PayByTraffic，PayByBandwidth。
+	InternetChargeType string `json:"internetChargeType,omitempty"`
+}
+
+// DiskCategory is the category of an ECS cloud disk.
+type DiskCategory string
+
+var (
+	// DiskCategoryCloud is the basic disk category
+	DiskCategoryCloud = DiskCategory("cloud")
+
+	// DiskCategoryCloudEfficiency is the ultra disk category
+	DiskCategoryCloudEfficiency = DiskCategory("cloud_efficiency")
+
+	// DiskCategoryCloudSSD is the standard SSD disk category
+	DiskCategoryCloudSSD = DiskCategory("cloud_ssd")
+
+	// DiskCategoryCloudESSD is the enhanced SSD disk category
+	DiskCategoryCloudESSD = DiskCategory("cloud_essd")
+)
+
+// DiskPerformanceLevel is the performance level of an enhanced SSD.
+type DiskPerformanceLevel string
+
+var (
+	// DiskPerformanceLevelPL0 is the lowest ESSD performance level
+	DiskPerformanceLevelPL0 = DiskPerformanceLevel("PL0")
+
+	// DiskPerformanceLevelPL1 is the default ESSD performance level
+	DiskPerformanceLevelPL1 = DiskPerformanceLevel("PL1")
+
+	// DiskPerformanceLevelPL2 is the ESSD performance level for larger disks
+	DiskPerformanceLevelPL2 = DiskPerformanceLevel("PL2")
+
+	// DiskPerformanceLevelPL3 is the highest ESSD performance level
+	DiskPerformanceLevelPL3 = DiskPerformanceLevel("PL3")
+)
+
+// VolumeType is the role of a disk attached to an instance.
+type VolumeType string
+
+var (
+	// VolumeTypeSystem is the system disk of the instance
+	VolumeTypeSystem = VolumeType("system")
+
+	// VolumeTypeData is a data disk of the instance
+	VolumeTypeData = VolumeType("data")
+)
+
+// Volume is a cloud disk attached to the instance.
+type Volume struct {
+	// 云盘ID
+	ID string `json:"id"`
+	// 云盘名称
+	// +optional
+	Name string `json:"name,omitempty"`
+	// 云盘挂载的实例上的设备名，例如/dev/xvdb。
+	// +optional
+	Device string `json:"device,omitempty"`
+	// 云盘类型。可能值：system：系统盘。data：数据盘。
+	// +optional
+	Type VolumeType `json:"type,omitempty"`
+	// +optional
+	Category string `json:"category,omitempty"`
+	// 云盘大小，单位GiB。
+	// +optional
+	Size int64 `json:"size,omitempty"`
+	// +optional
+	PerformanceLevel string `json:"performanceLevel,omitempty"`
+}
+
+// InternetChargeType is the billing method of the public network bandwidth.
+type InternetChargeType string
+
+var (
+	// InternetChargeTypePayByBandwidth charges by the fixed bandwidth
+	InternetChargeTypePayByBandwidth = InternetChargeType("PayByBandwidth")
+
+	// InternetChargeTypePayByTraffic charges by the used traffic
+	InternetChargeTypePayByTraffic = InternetChargeType("PayByTraffic")
+)
+
+// SpotStrategy is the bidding policy of a pay-as-you-go instance.
+type SpotStrategy string
+
+var (
+	// SpotStrategyNoSpot runs a normal pay-as-you-go instance
+	SpotStrategyNoSpot = SpotStrategy("NoSpot")
+
+	// SpotStrategySpotWithPriceLimit runs a spot instance with a max hourly price
+	SpotStrategySpotWithPriceLimit = SpotStrategy("SpotWithPriceLimit")
+
+	// SpotStrategySpotAsPriceGo runs a spot instance at the market price
+	SpotStrategySpotAsPriceGo = SpotStrategy("SpotAsPriceGo")
+)
+
+// InstanceChargeType is the billing method of an ECS instance.
+type InstanceChargeType string
+
+var (
+	// InstanceChargeTypePrePaid is the subscription billing method
+	InstanceChargeTypePrePaid = InstanceChargeType("PrePaid")
+
+	// InstanceChargeTypePostPaid is the pay-as-you-go billing method
+	InstanceChargeTypePostPaid = InstanceChargeType("PostPaid")
+)
+
+// PeriodUnit is the unit of the subscription period of a PrePaid instance.
+type PeriodUnit string
+
+var (
+	// PeriodUnitWeek subscribes the instance by weeks
+	PeriodUnitWeek = PeriodUnit("Week")
+
+	// PeriodUnitMonth subscribes the instance by months
+	PeriodUnitMonth = PeriodUnit("Month")
+)
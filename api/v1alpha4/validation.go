@@ -0,0 +1,160 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// diskSizeRange is the inclusive size range of a disk in GiB.
+type diskSizeRange struct {
+	min, max int64
+}
+
+var (
+	// system disk size, check for details: https://help.aliyun.com/document_detail/25412.html
+	systemDiskSizeRange = diskSizeRange{min: 20, max: 500}
+
+	// data disk size per category
+	dataDiskSizeRanges = map[DiskCategory]diskSizeRange{
+		DiskCategoryCloud:           {min: 5, max: 2000},
+		DiskCategoryCloudEfficiency: {min: 20, max: 32768},
+		DiskCategoryCloudSSD:        {min: 20, max: 32768},
+		DiskCategoryCloudESSD:       {min: 20, max: 32768},
+	}
+)
+
+var (
+	// minimal size of an enhanced SSD per performance level, check for details: https://help.aliyun.com/document_detail/122389.html
+	essdMinSizes = map[DiskPerformanceLevel]int64{
+		DiskPerformanceLevelPL0: 40,
+		DiskPerformanceLevelPL1: 20,
+		DiskPerformanceLevelPL2: 461,
+		DiskPerformanceLevelPL3: 1261,
+	}
+)
+
+var (
+	// subscription period per unit, check for details: https://help.aliyun.com/document_detail/63440.html
+	periods = map[PeriodUnit][]int64{
+		PeriodUnitWeek:  {1, 2, 3, 4},
+		PeriodUnitMonth: {1, 2, 3, 4, 5, 6, 7, 8, 9, 12, 24, 36, 48, 60},
+	}
+
+	// auto renew period per unit
+	autoRenewPeriods = map[PeriodUnit][]int64{
+		PeriodUnitWeek:  {1, 2, 3},
+		PeriodUnitMonth: {1, 2, 3, 6, 12},
+	}
+)
+
+func validateDiskCategory(category string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if category == "" {
+		return allErrs
+	}
+	if _, ok := dataDiskSizeRanges[DiskCategory(category)]; !ok {
+		allErrs = append(allErrs, field.NotSupported(fldPath, category, []string{
+			string(DiskCategoryCloud),
+			string(DiskCategoryCloudEfficiency),
+			string(DiskCategoryCloudSSD),
+			string(DiskCategoryCloudESSD),
+		}))
+	}
+	return allErrs
+}
+
+func validateSystemDiskSize(size string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if size == "" {
+		return allErrs
+	}
+	value, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath, size, "must be an integer in GiB"))
+	}
+	allErrs = append(allErrs, validateDiskSizeRange(value, systemDiskSizeRange, fldPath)...)
+	return allErrs
+}
+
+func validateDataDiskSize(category string, size int64, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if size == 0 {
+		return allErrs
+	}
+	sizeRange, ok := dataDiskSizeRanges[DiskCategory(category)]
+	if !ok {
+		// an empty category falls back to the ECS default cloud_efficiency
+		sizeRange = dataDiskSizeRanges[DiskCategoryCloudEfficiency]
+	}
+	allErrs = append(allErrs, validateDiskSizeRange(size, sizeRange, fldPath)...)
+	return allErrs
+}
+
+func validateDiskSizeRange(size int64, sizeRange diskSizeRange, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if size < sizeRange.min || size > sizeRange.max {
+		allErrs = append(allErrs, field.Invalid(fldPath, size,
+			fmt.Sprintf("must be between %d and %d GiB", sizeRange.min, sizeRange.max)))
+	}
+	return allErrs
+}
+
+func validatePeriod(period int64, allowed []int64, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, p := range allowed {
+		if period == p {
+			return allErrs
+		}
+	}
+	values := make([]string, 0, len(allowed))
+	for _, p := range allowed {
+		values = append(values, strconv.FormatInt(p, 10))
+	}
+	return append(allErrs, field.NotSupported(fldPath, period, values))
+}
+
+func validatePerformanceLevel(category, level string, size int64, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if DiskCategory(category) != DiskCategoryCloudESSD {
+		if level != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "performanceLevel is only supported by cloud_essd disks"))
+		}
+		return allErrs
+	}
+
+	if level == "" {
+		level = string(DiskPerformanceLevelPL1)
+	}
+	minSize, ok := essdMinSizes[DiskPerformanceLevel(level)]
+	if !ok {
+		return append(allErrs, field.NotSupported(fldPath, level, []string{
+			string(DiskPerformanceLevelPL0),
+			string(DiskPerformanceLevelPL1),
+			string(DiskPerformanceLevelPL2),
+			string(DiskPerformanceLevelPL3),
+		}))
+	}
+	if size != 0 && size < minSize {
+		allErrs = append(allErrs, field.Invalid(fldPath, level,
+			fmt.Sprintf("%s requires a disk of at least %d GiB, got %d GiB", level, minSize, size)))
+	}
+	return allErrs
+}
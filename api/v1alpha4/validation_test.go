@@ -0,0 +1,180 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha4
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidatePerformanceLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		category DiskCategory
+		level    DiskPerformanceLevel
+		size     int64
+		valid    bool
+	}{
+		{name: "PL0 minimum size", category: DiskCategoryCloudESSD, level: DiskPerformanceLevelPL0, size: 40, valid: true},
+		{name: "PL0 too small", category: DiskCategoryCloudESSD, level: DiskPerformanceLevelPL0, size: 39},
+		{name: "PL1 minimum size", category: DiskCategoryCloudESSD, level: DiskPerformanceLevelPL1, size: 20, valid: true},
+		{name: "PL1 too small", category: DiskCategoryCloudESSD, level: DiskPerformanceLevelPL1, size: 19},
+		{name: "PL2 minimum size", category: DiskCategoryCloudESSD, level: DiskPerformanceLevelPL2, size: 461, valid: true},
+		{name: "PL2 too small", category: DiskCategoryCloudESSD, level: DiskPerformanceLevelPL2, size: 460},
+		{name: "PL3 minimum size", category: DiskCategoryCloudESSD, level: DiskPerformanceLevelPL3, size: 1261, valid: true},
+		{name: "PL3 too small", category: DiskCategoryCloudESSD, level: DiskPerformanceLevelPL3, size: 1260},
+		{name: "default PL1 too small", category: DiskCategoryCloudESSD, size: 19},
+		{name: "size of a snapshot", category: DiskCategoryCloudESSD, level: DiskPerformanceLevelPL3, valid: true},
+		{name: "unknown level", category: DiskCategoryCloudESSD, level: "PL4", size: 2000},
+		{name: "level of another category", category: DiskCategoryCloudSSD, level: DiskPerformanceLevelPL1, size: 100},
+		{name: "no level of another category", category: DiskCategoryCloudSSD, size: 100, valid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validatePerformanceLevel(string(tt.category), string(tt.level), tt.size, field.NewPath("performanceLevel"))
+			if tt.valid {
+				g.Expect(errs).To(BeEmpty())
+			} else {
+				g.Expect(errs).NotTo(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateChargeType(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  ACKMachineSpec
+		valid bool
+	}{
+		{name: "PostPaid", spec: ACKMachineSpec{}, valid: true},
+		{name: "PostPaid with period", spec: ACKMachineSpec{Period: 1}},
+		{name: "PostPaid with period unit", spec: ACKMachineSpec{PeriodUnit: string(PeriodUnitMonth)}},
+		{name: "PostPaid with auto renew", spec: ACKMachineSpec{AutoRenew: true}},
+		{name: "PrePaid without period", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid)}},
+		{name: "PrePaid monthly", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 12}, valid: true},
+		{name: "PrePaid five years", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 60}, valid: true},
+		{name: "PrePaid unsupported month period", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 10}},
+		{name: "PrePaid weekly", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 4, PeriodUnit: string(PeriodUnitWeek)}, valid: true},
+		{name: "PrePaid unsupported week period", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 5, PeriodUnit: string(PeriodUnitWeek)}},
+		{name: "PrePaid unknown period unit", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 1, PeriodUnit: "Year"}},
+		{name: "PrePaid auto renew", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 1, AutoRenew: true, AutoRenewPeriod: 6}, valid: true},
+		{name: "PrePaid auto renew without period", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 1, AutoRenew: true}},
+		{name: "PrePaid unsupported auto renew period", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 1, AutoRenew: true, AutoRenewPeriod: 4}},
+		{name: "PrePaid weekly auto renew", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 1, PeriodUnit: string(PeriodUnitWeek), AutoRenew: true, AutoRenewPeriod: 3}, valid: true},
+		{name: "PrePaid auto renew period without auto renew", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 1, AutoRenewPeriod: 1}},
+		{name: "PrePaid spot", spec: ACKMachineSpec{InstanceChargeType: string(InstanceChargeTypePrePaid), Period: 1, SpotOptions: &SpotOptions{SpotStrategy: SpotStrategySpotAsPriceGo}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateChargeType(&tt.spec, field.NewPath("spec"))
+			if tt.valid {
+				g.Expect(errs).To(BeEmpty())
+			} else {
+				g.Expect(errs).NotTo(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateSystemDiskSize(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  string
+		valid bool
+	}{
+		{name: "default size", size: "", valid: true},
+		{name: "minimum size", size: "20", valid: true},
+		{name: "maximum size", size: "500", valid: true},
+		{name: "too small", size: "19"},
+		{name: "too large", size: "501"},
+		{name: "not an integer", size: "40Gi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateSystemDiskSize(tt.size, field.NewPath("size"))
+			if tt.valid {
+				g.Expect(errs).To(BeEmpty())
+			} else {
+				g.Expect(errs).NotTo(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateDataDiskSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		category DiskCategory
+		size     int64
+		valid    bool
+	}{
+		{name: "size of a snapshot", category: DiskCategoryCloud, valid: true},
+		{name: "cloud minimum size", category: DiskCategoryCloud, size: 5, valid: true},
+		{name: "cloud too small", category: DiskCategoryCloud, size: 4},
+		{name: "cloud maximum size", category: DiskCategoryCloud, size: 2000, valid: true},
+		{name: "cloud too large", category: DiskCategoryCloud, size: 2001},
+		{name: "cloud_efficiency minimum size", category: DiskCategoryCloudEfficiency, size: 20, valid: true},
+		{name: "cloud_efficiency too small", category: DiskCategoryCloudEfficiency, size: 19},
+		{name: "cloud_ssd maximum size", category: DiskCategoryCloudSSD, size: 32768, valid: true},
+		{name: "cloud_ssd too large", category: DiskCategoryCloudSSD, size: 32769},
+		{name: "cloud_essd too small", category: DiskCategoryCloudESSD, size: 19},
+		{name: "default category minimum size", size: 20, valid: true},
+		{name: "default category too small", size: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateDataDiskSize(string(tt.category), tt.size, field.NewPath("size"))
+			if tt.valid {
+				g.Expect(errs).To(BeEmpty())
+			} else {
+				g.Expect(errs).NotTo(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestValidateDiskCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		category string
+		valid    bool
+	}{
+		{name: "default category", category: "", valid: true},
+		{name: "cloud", category: string(DiskCategoryCloud), valid: true},
+		{name: "cloud_essd", category: string(DiskCategoryCloudESSD), valid: true},
+		{name: "unknown category", category: "ephemeral_ssd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			errs := validateDiskCategory(tt.category, field.NewPath("category"))
+			if tt.valid {
+				g.Expect(errs).To(BeEmpty())
+			} else {
+				g.Expect(errs).NotTo(BeEmpty())
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// image-builder builds node images with kubeadm, kubelet and kubectl preinstalled.
+//
+// It launches a builder instance from a base image, installs the Kubernetes components
+// over cloud-init, creates an image from the stopped instance and tags it with the
+// Kubernetes version, so ACKMachines can find it with an image lookup. The image may be
+// shared with other accounts and copied to other regions.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	"k8s.io/klog/klogr"
+)
+
+const (
+	// pollInterval is the interval the builder instance and the image are polled at
+	pollInterval = 15 * time.Second
+)
+
+// userDataTemplate installs the container runtime and the Kubernetes components and powers
+// the instance off when done. The cloud-init state is cleaned, so instances created from the
+// image run cloud-init again.
+var userDataTemplate = template.Must(template.New("userData").Parse(`#cloud-config
+write_files:
+- path: /etc/yum.repos.d/kubernetes.repo
+  content: |
+    [kubernetes]
+    name=Kubernetes
+    baseurl={{ .Repository }}
+    enabled=1
+    gpgcheck=0
+- path: /etc/modules-load.d/kubernetes.conf
+  content: |
+    br_netfilter
+- path: /etc/sysctl.d/kubernetes.conf
+  content: |
+    net.bridge.bridge-nf-call-iptables = 1
+    net.bridge.bridge-nf-call-ip6tables = 1
+    net.ipv4.ip_forward = 1
+runcmd:
+- yum install -y docker kubelet-{{ .Version }} kubeadm-{{ .Version }} kubectl-{{ .Version }}
+- systemctl enable docker kubelet
+- kubeadm config images pull --kubernetes-version v{{ .Version }} --image-repository {{ .ImageRepository }} || true
+- cloud-init clean --logs
+power_state:
+  mode: poweroff
+  condition: true
+`))
+
+type options struct {
+	region            string
+	zone              string
+	baseImageID       string
+	kubernetesVersion string
+	instanceType      string
+	vswitchID         string
+	securityGroupID   string
+	bandwidth         int64
+	imageName         string
+	imageFamily       string
+	resourceGroupID   string
+	repository        string
+	imageRepository   string
+	shareAccounts     string
+	copyRegions       string
+	timeout           time.Duration
+}
+
+func main() {
+	klog.InitFlags(nil)
+
+	o := options{}
+	flag.StringVar(&o.region, "region", "", "The region to build the image in.")
+	flag.StringVar(&o.zone, "zone", "", "The zone of the builder instance, defaults to the zone of the vswitch.")
+	flag.StringVar(&o.baseImageID, "base-image-id", "", "The ID of the CentOS or Aliyun Linux image to build upon.")
+	flag.StringVar(&o.kubernetesVersion, "kubernetes-version", "", "The Kubernetes version to install, e.g. v1.18.2.")
+	flag.StringVar(&o.instanceType, "instance-type", "ecs.g6.large", "The instance type of the builder instance.")
+	flag.StringVar(&o.vswitchID, "vswitch-id", "", "The vswitch of the builder instance.")
+	flag.StringVar(&o.securityGroupID, "security-group-id", "", "The security group of the builder instance.")
+	flag.Int64Var(&o.bandwidth, "internet-max-bandwidth-out", 10, "The outbound internet bandwidth of the builder instance in Mbit/s, used to download the components.")
+	flag.StringVar(&o.imageName, "image-name", "", "The name of the image, defaults to capi-<kubernetes-version>-<timestamp>.")
+	flag.StringVar(&o.imageFamily, "image-family", "", "The image family of the image.")
+	flag.StringVar(&o.resourceGroupID, "resource-group-id", "", "The resource group the builder instance and the image are created in.")
+	flag.StringVar(&o.repository, "repository", "https://mirrors.aliyun.com/kubernetes/yum/repos/kubernetes-el7-x86_64/", "The yum repository of the Kubernetes components.")
+	flag.StringVar(&o.imageRepository, "image-repository", "registry.aliyuncs.com/google_containers", "The registry the control plane images are pre-pulled from.")
+	flag.StringVar(&o.shareAccounts, "share-accounts", "", "Comma separated aliyun account IDs to share the image with.")
+	flag.StringVar(&o.copyRegions, "copy-regions", "", "Comma separated regions to copy the image to.")
+	flag.DurationVar(&o.timeout, "timeout", 45*time.Minute, "The timeout of installing the components and of creating the image.")
+	flag.Parse()
+
+	if err := run(&o); err != nil {
+		klog.Errorf("failed to build image: %v", err)
+		os.Exit(1)
+	}
+}
+
+func run(o *options) error {
+	if o.region == "" || o.baseImageID == "" || o.kubernetesVersion == "" || o.vswitchID == "" || o.securityGroupID == "" {
+		return errors.New("region, base-image-id, kubernetes-version, vswitch-id and security-group-id are required")
+	}
+	version := strings.TrimPrefix(o.kubernetesVersion, "v")
+	if o.imageName == "" {
+		o.imageName = fmt.Sprintf("capi-v%s-%s", version, time.Now().Format("20060102150405"))
+	}
+
+	log := klogr.New()
+	regionScope, err := scope.NewRegionScope(scope.RegionScopeParams{Logger: log, Region: o.region})
+	if err != nil {
+		return err
+	}
+	ecsSvc := ecs.NewService(regionScope)
+
+	userData := &bytes.Buffer{}
+	if err := userDataTemplate.Execute(userData, map[string]string{
+		"Version":         version,
+		"Repository":      o.repository,
+		"ImageRepository": o.imageRepository,
+	}); err != nil {
+		return errors.Wrap(err, "failed to render user data")
+	}
+
+	// the builder instance and the image are tagged alike
+	tags := infrav1.Tags{
+		ecs.ImageTagKubernetesVersion: "v" + version,
+		"base-image-id":               o.baseImageID,
+	}
+	instance, err := launchBuilder(ecsSvc, log, o, userData.Bytes(), tags)
+	if err != nil {
+		return err
+	}
+	log.Info("Launched builder instance", "instance-id", instance.Id)
+	defer func() {
+		if err := ecsSvc.TerminateInstance(instance.Id); err != nil {
+			log.Error(err, "Failed to terminate builder instance", "instance-id", instance.Id)
+		}
+	}()
+
+	// cloud-init powers the instance off when the components are installed
+	if err := wait.PollImmediate(pollInterval, o.timeout, func() (bool, error) {
+		current, err := ecsSvc.InstanceIfExists(&instance.Id)
+		if err != nil || current == nil {
+			return false, err
+		}
+		return current.State == infrav1.InstanceStateStopped, nil
+	}); err != nil {
+		return errors.Wrapf(err, "builder instance %q did not stop", instance.Id)
+	}
+
+	image := &ecs.ImageSpec{
+		Name:            o.imageName,
+		Family:          o.imageFamily,
+		ResourceGroupId: o.resourceGroupID,
+		Description:     fmt.Sprintf("Kubernetes v%s node image built from %s", version, o.baseImageID),
+		Tags:            tags,
+	}
+	imageID, err := ecsSvc.CreateImage(instance.Id, image)
+	if err != nil {
+		return err
+	}
+	log.Info("Creating image", "image-id", imageID)
+
+	if err := wait.PollImmediate(pollInterval, o.timeout, func() (bool, error) {
+		status, err := ecsSvc.ImageStatus(imageID)
+		if err != nil {
+			return false, err
+		}
+		if status == ecs.ImageStatusCreateFailed {
+			return false, errors.Errorf("creating image %q failed", imageID)
+		}
+		return status == ecs.ImageStatusAvailable, nil
+	}); err != nil {
+		return errors.Wrapf(err, "image %q did not become available", imageID)
+	}
+	fmt.Printf("%s\t%s\n", o.region, imageID)
+
+	if o.shareAccounts != "" {
+		if err := ecsSvc.ShareImage(imageID, strings.Split(o.shareAccounts, ",")); err != nil {
+			return err
+		}
+	}
+	if o.copyRegions != "" {
+		for _, region := range strings.Split(o.copyRegions, ",") {
+			copyID, err := ecsSvc.CopyImage(imageID, region, image)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s\t%s\n", region, copyID)
+		}
+	}
+	return nil
+}
+
+// launchBuilder runs the builder instance in the resource group of the image. The builder
+// instance is described as an ACKMachine to create it through the ecs service.
+func launchBuilder(ecsSvc *ecs.Service, log logr.Logger, o *options, userData []byte, tags infrav1.Tags) (*infrav1.Instance, error) {
+	builder := &infrav1.ACKMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: o.imageName},
+		Spec: infrav1.ACKMachineSpec{
+			RegionId:           o.region,
+			ZoneId:             o.zone,
+			InstanceType:       o.instanceType,
+			InstanceName:       o.imageName + "-builder",
+			IoOptimized:        "optimized",
+			ImageId:            o.baseImageID,
+			InstanceChargeType: string(infrav1.InstanceChargeTypePostPaid),
+			MachineNetworkSpec: infrav1.MachineNetworkSpec{
+				SecurityGroupId:         o.securityGroupID,
+				VSwitchId:               o.vswitchID,
+				InternetMaxBandwidthOut: o.bandwidth,
+				InternetChargeType:      string(infrav1.InternetChargeTypePayByTraffic),
+			},
+		},
+	}
+	return ecsSvc.CreateInstance(&scope.MachineScope{Logger: log, ACKMachine: builder}, userData, o.resourceGroupID, tags)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	sdkecs "github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"k8s.io/klog/klogr"
+)
+
+func TestLaunchBuilder(t *testing.T) {
+	g := NewWithT(t)
+
+	// the fake ecs api records the RunInstances request
+	var runInstances url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.ParseForm()).To(Succeed())
+		g.Expect(r.Form.Get("Action")).To(Equal("RunInstances"))
+		runInstances = r.Form
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"RequestId":"request","InstanceIdSets":{"InstanceIdSet":["i-builder"]}}`))
+	}))
+	defer server.Close()
+
+	ecsClient, err := sdkecs.NewClientWithAccessKey("cn-hangzhou", "id", "secret")
+	g.Expect(err).NotTo(HaveOccurred())
+	ecsClient.Domain = strings.TrimPrefix(server.URL, "http://")
+	vpcClient, err := vpc.NewClientWithAccessKey("cn-hangzhou", "id", "secret")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	regionScope, err := scope.NewRegionScope(scope.RegionScopeParams{
+		ACKClients: scope.ACKClients{ECS: ecsClient, VPC: vpcClient},
+		Region:     "cn-hangzhou",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	o := &options{
+		region:          "cn-hangzhou",
+		baseImageID:     "centos_7",
+		instanceType:    "ecs.g6.large",
+		vswitchID:       "vsw-builder",
+		securityGroupID: "sg-builder",
+		bandwidth:       10,
+		imageName:       "capi-v1.18.2",
+		resourceGroupID: "rg-images",
+	}
+	tags := infrav1.Tags{ecs.ImageTagKubernetesVersion: "v1.18.2"}
+
+	instance, err := launchBuilder(ecs.NewService(regionScope), klogr.New(), o, []byte("#cloud-config"), tags)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(instance.Id).To(Equal("i-builder"))
+
+	g.Expect(runInstances.Get("ResourceGroupId")).To(Equal("rg-images"))
+	g.Expect(runInstances.Get("VSwitchId")).To(Equal("vsw-builder"))
+	g.Expect(runInstances.Get("InstanceName")).To(Equal("capi-v1.18.2-builder"))
+	g.Expect(runInstances.Get("Tag.1.Key")).To(Equal(ecs.ImageTagKubernetesVersion))
+	g.Expect(runInstances.Get("Tag.1.Value")).To(Equal("v1.18.2"))
+}
@@ -18,10 +18,22 @@ package controllers
 
 import (
 	"context"
+	"time"
+
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/aliyunerrors"
 	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/resourcemanager"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/slb"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/vpc"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/go-logr/logr"
@@ -30,27 +42,63 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	ackv1alpha3 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha3"
+	ackv1alpha4 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
 )
 
+// infrastructureRequeueAfter is how long to wait for the network and the load balancer of the
+// control plane to become available.
+const infrastructureRequeueAfter = 30 * time.Second
+
 // ACKClusterReconciler reconciles a ACKCluster object
 type ACKClusterReconciler struct {
 	client.Client
-	Log      logr.Logger
-	Scheme   *runtime.Scheme
-	Recorder record.EventRecorder
+	Log               logr.Logger
+	Scheme            *runtime.Scheme
+	Recorder          record.EventRecorder
+	vpcServiceFactory func(*scope.ClusterScope) services.VPCInterface
+	ecsServiceFactory func(*scope.ClusterScope) services.ECSClusterInterface
+	slbServiceFactory func(*scope.ClusterScope) services.SLBInterface
+	// resourceManagerServiceFactory creates the service checking resource groups
+	resourceManagerServiceFactory func(*scope.ClusterScope) services.ResourceManagerInterface
+}
+
+func (r *ACKClusterReconciler) getVPCService(scope *scope.ClusterScope) services.VPCInterface {
+	if r.vpcServiceFactory != nil {
+		return r.vpcServiceFactory(scope)
+	}
+	return vpc.NewService(scope)
+}
+
+func (r *ACKClusterReconciler) getECSService(scope *scope.ClusterScope) services.ECSClusterInterface {
+	if r.ecsServiceFactory != nil {
+		return r.ecsServiceFactory(scope)
+	}
+	return ecs.NewService(scope)
+}
+
+func (r *ACKClusterReconciler) getSLBService(scope *scope.ClusterScope) services.SLBInterface {
+	if r.slbServiceFactory != nil {
+		return r.slbServiceFactory(scope)
+	}
+	return slb.NewService(scope)
+}
+
+func (r *ACKClusterReconciler) getResourceManagerService(scope *scope.ClusterScope) services.ResourceManagerInterface {
+	if r.resourceManagerServiceFactory != nil {
+		return r.resourceManagerServiceFactory(scope)
+	}
+	return resourcemanager.NewService(scope)
 }
 
 // +kubebuilder:rbac:groups=ack.cluster.k8s.io,resources=ackclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ack.cluster.k8s.io,resources=ackclusters/status,verbs=get;update;patch
 
-func (r *ACKClusterReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
-	var reterr error
+func (r *ACKClusterReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
 	ctx := context.Background()
 	logger := r.Log.WithValues("ackcluster", req.NamespacedName, "ackCluster", req.Name)
 
 	// fetch the ACKCluster instance
-	ackCluster := &ackv1alpha3.ACKCluster{}
+	ackCluster := &ackv1alpha4.ACKCluster{}
 	err := r.Get(ctx, req.NamespacedName, ackCluster)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
@@ -62,8 +110,7 @@ func (r *ACKClusterReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 	// fetch the Cluster (cluster-api)
 	cluster, err := util.GetOwnerCluster(ctx, r.Client, ackCluster.ObjectMeta)
 	if err != nil {
-		logger.Error(err, "failed to fetch the Cluster")
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, errors.Wrap(err, "failed to fetch the Cluster")
 	}
 	if cluster == nil {
 		logger.Info("Cluster Controller has not yet set OwnerRef")
@@ -94,28 +141,186 @@ func (r *ACKClusterReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		}
 	}()
 
+	// Handle deleted clusters
 	if r.IsDeletedACKCluster(ackCluster) {
-		// todo: reconcile deleted
-		_, err = clusterScope.ReconcileDelete()
-		if err != nil {
+		result, err := r.reconcileDelete(clusterScope)
+		return r.handleReconcileError(clusterScope, capierrors.DeleteClusterError, result, err)
+	}
+
+	// Handle non-deleted clusters
+	reason := capierrors.UpdateClusterError
+	if !ackCluster.Status.Ready {
+		reason = capierrors.CreateClusterError
+	}
+	result, err := r.reconcileNormal(clusterScope)
+	return r.handleReconcileError(clusterScope, reason, result, err)
+}
 
+// handleReconcileError requeues the ACKCluster with backoff on transient errors of the aliyun
+// api, and sets the failure reason and message on terminal errors which retrying can't fix.
+// A deleted ACKCluster keeps being retried so that its finalizer is removed once the problem
+// has been fixed.
+func (r *ACKClusterReconciler) handleReconcileError(clusterScope *scope.ClusterScope, operation capierrors.ClusterStatusError, result ctrl.Result, err error) (ctrl.Result, error) {
+	if err == nil {
+		return result, nil
+	}
+	if aliyunerrors.IsRetryable(err) {
+		// requeueing without an error backs off exponentially on the rate limiter of the controller
+		clusterScope.Info("Transient error reconciling ACKCluster, requeueing", "error", err.Error())
+		return ctrl.Result{Requeue: true}, nil
+	}
+	if reason, terminal := aliyunerrors.ClusterStatusError(err, operation); terminal {
+		clusterScope.SetFailureReason(reason)
+		clusterScope.SetFailureMessage(err)
+		r.Recorder.Eventf(clusterScope.ACKCluster, corev1.EventTypeWarning, "ReconcileFailed", "Reconciling ACKCluster failed: %v", err)
+		if operation == capierrors.DeleteClusterError {
+			return ctrl.Result{}, err
 		}
-		//return reconcileDelete(clusterScope)
+		return ctrl.Result{}, nil
 	}
-	_, err = clusterScope.ReconcileNormal()
-	if err != nil {
+	return result, err
+}
 
+func (r *ACKClusterReconciler) reconcileNormal(clusterScope *scope.ClusterScope) (ctrl.Result, error) {
+	clusterScope.Info("Reconciling ACKCluster")
+	ackCluster := clusterScope.ACKCluster
+
+	// a terminal failure needs the spec or the account to be fixed first
+	if clusterScope.HasFailed() {
+		return ctrl.Result{}, nil
 	}
 
+	// add finalizer if not exits
+	controllerutil.AddFinalizer(ackCluster, scope.ClusterFinalizer)
+	// Register the finalizer immediately to avoid orphaning ACK resources on delete
+	if err := clusterScope.PatchObject(); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// all resources of the cluster are created in the resource group
+	if id := clusterScope.ResourceGroupID(); id != "" {
+		if err := r.getResourceManagerService(clusterScope).CheckResourceGroup(id); err != nil {
+			r.Recorder.Eventf(ackCluster, corev1.EventTypeWarning, "FailedResourceGroup", "Failed to check resource group: %v", err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	vpcSvc := r.getVPCService(clusterScope)
+	if err := r.reconcileFailureDomains(clusterScope, vpcSvc); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile failure domains for ACKCluster %s/%s", ackCluster.Namespace, ackCluster.Name)
+	}
+
+	if err := r.reconcileTags(clusterScope, vpcSvc); err != nil {
+		r.Recorder.Eventf(ackCluster, corev1.EventTypeWarning, "FailedTagResources", "Failed to tag resources: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	// the provider doesn't create the network and the load balancer of the control plane, the
+	// ACKCluster only becomes ready once the ones of the spec have been verified
+	notReady, err := r.checkInfrastructure(clusterScope, vpcSvc, r.getSLBService(clusterScope))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if notReady != "" {
+		clusterScope.Info("Waiting for the cluster infrastructure", "reason", notReady)
+		r.Recorder.Eventf(ackCluster, corev1.EventTypeWarning, "InfrastructureNotReady", "Cluster infrastructure is not ready: %s", notReady)
+		ackCluster.Status.Ready = false
+		return ctrl.Result{RequeueAfter: infrastructureRequeueAfter}, nil
+	}
+
+	ackCluster.Status.Ready = true
 	return ctrl.Result{}, nil
 }
 
+// checkInfrastructure returns why the vpc, the vswitches or the load balancer of the control
+// plane endpoint can't be used yet, or nothing if they are available. The ID of the load
+// balancer is recorded in the status.
+func (r *ACKClusterReconciler) checkInfrastructure(clusterScope *scope.ClusterScope, vpcSvc services.VPCInterface, slbSvc services.SLBInterface) (string, error) {
+	ackCluster := clusterScope.ACKCluster
+	network := ackCluster.Spec.NetworkSpec
+	if network.VpcId == "" {
+		return "networkSpec.vpcId is not set, the vpc and the vswitches are not created by the provider", nil
+	}
+	vswitchIDs := append(append([]string{}, network.MasterVswitchIds...), network.WorkerVswitchIds...)
+	notReady, err := vpcSvc.CheckNetwork(network.VpcId, vswitchIDs)
+	if err != nil || notReady != "" {
+		return notReady, err
+	}
+	ackCluster.Status.VpcId = network.VpcId
+
+	endpoint := ackCluster.Spec.ControlPlaneEndpoint
+	if endpoint.Host == "" {
+		return "controlPlaneEndpoint is not set, the load balancer of the control plane is not created by the provider", nil
+	}
+	id, notReady, err := slbSvc.CheckLoadBalancer(endpoint.Host)
+	if err != nil || notReady != "" {
+		return notReady, err
+	}
+	ackCluster.Status.IntranetSlbId = id
+	return "", nil
+}
+
+// reconcileFailureDomains publishes the zones of the master and worker vswitches as failure
+// domains, only the zones of the master vswitches are eligible for control plane machines.
+func (r *ACKClusterReconciler) reconcileFailureDomains(clusterScope *scope.ClusterScope, vpcSvc services.VPCInterface) error {
+	network := clusterScope.ACKCluster.Spec.NetworkSpec
+
+	masters, err := vpcSvc.DescribeVSwitches(network.MasterVswitchIds)
+	if err != nil {
+		return err
+	}
+	workers, err := vpcSvc.DescribeVSwitches(network.WorkerVswitchIds)
+	if err != nil {
+		return err
+	}
+
+	failureDomains := clusterv1.FailureDomains{}
+	for _, vswitch := range workers {
+		failureDomains[vswitch.ZoneId] = clusterv1.FailureDomainSpec{}
+	}
+	for _, vswitch := range masters {
+		failureDomains[vswitch.ZoneId] = clusterv1.FailureDomainSpec{ControlPlane: true}
+	}
+
+	clusterScope.ACKCluster.Status.MasterVSwitches = masters
+	clusterScope.ACKCluster.Status.WorkerVSwitches = workers
+	clusterScope.SetFailureDomains(failureDomains)
+	return nil
+}
+
+// reconcileTags applies the tags of the ACKCluster to the vpc and the vswitches of the cluster,
+// they are provided by the user and thus shared, and corrects them when they drift.
+func (r *ACKClusterReconciler) reconcileTags(clusterScope *scope.ClusterScope, vpcSvc services.VPCInterface) error {
+	network := clusterScope.ACKCluster.Spec.NetworkSpec
+	additional := clusterScope.AdditionalTags()
+	removed, err := removedTags(clusterScope.ACKCluster, additional)
+	if err != nil {
+		return err
+	}
+	tags := clusterScope.Tags(ackv1alpha4.ResourceLifecycleShared)
+
+	if network.VpcId != "" {
+		if err := vpcSvc.EnsureTags(vpc.ResourceTypeVPC, []string{network.VpcId}, tags, removed); err != nil {
+			return err
+		}
+	}
+
+	var vswitches []string
+	vswitches = append(vswitches, network.MasterVswitchIds...)
+	vswitches = append(vswitches, network.WorkerVswitchIds...)
+	if err := vpcSvc.EnsureTags(vpc.ResourceTypeVSwitch, vswitches, tags, removed); err != nil {
+		return err
+	}
+
+	return setLastAppliedTags(clusterScope.ACKCluster, additional)
+}
+
 func (r *ACKClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&ackv1alpha3.ACKCluster{}).
+		For(&ackv1alpha4.ACKCluster{}).
 		Complete(r)
 }
 
-func (r *ACKClusterReconciler) IsDeletedACKCluster(ackCluster *ackv1alpha3.ACKCluster) bool {
+func (r *ACKClusterReconciler) IsDeletedACKCluster(ackCluster *ackv1alpha4.ACKCluster) bool {
 	return !ackCluster.DeletionTimestamp.IsZero()
 }
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+// fakeNetworkService reports the vpc and vswitches as given.
+type fakeNetworkService struct {
+	services.VPCInterface
+	notReady  string
+	vswitches []string
+}
+
+func (s *fakeNetworkService) CheckNetwork(vpcID string, vswitchIDs []string) (string, error) {
+	s.vswitches = vswitchIDs
+	return s.notReady, nil
+}
+
+// fakeLoadBalancerService reports the load balancer of the control plane as given.
+type fakeLoadBalancerService struct {
+	services.SLBInterface
+	notReady string
+}
+
+func (s *fakeLoadBalancerService) CheckLoadBalancer(address string) (string, string, error) {
+	return "lb-" + address, s.notReady, nil
+}
+
+func TestCheckInfrastructure(t *testing.T) {
+	network := infrav1.NetworkSpec{
+		VpcId:            "vpc-1",
+		MasterVswitchIds: []string{"vsw-master"},
+		WorkerVswitchIds: []string{"vsw-worker"},
+	}
+	endpoint := clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443}
+
+	tests := []struct {
+		name                 string
+		network              infrav1.NetworkSpec
+		endpoint             clusterv1.APIEndpoint
+		networkNotReady      string
+		loadBalancerNotReady string
+		ready                bool
+	}{
+		{name: "available", network: network, endpoint: endpoint, ready: true},
+		{name: "vpc not given", endpoint: endpoint},
+		{name: "vpc not available", network: network, endpoint: endpoint, networkNotReady: "vpc vpc-1 is Pending"},
+		{name: "endpoint not given", network: network},
+		{name: "load balancer not active", network: network, endpoint: endpoint, loadBalancerNotReady: "load balancer lb-1 is inactive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterScope := &scope.ClusterScope{ACKCluster: &infrav1.ACKCluster{
+				Spec: infrav1.ACKClusterSpec{NetworkSpec: tt.network, ControlPlaneEndpoint: tt.endpoint},
+			}}
+			vpcSvc := &fakeNetworkService{notReady: tt.networkNotReady}
+			slbSvc := &fakeLoadBalancerService{notReady: tt.loadBalancerNotReady}
+
+			notReady, err := (&ACKClusterReconciler{}).checkInfrastructure(clusterScope, vpcSvc, slbSvc)
+			g.Expect(err).NotTo(HaveOccurred())
+			if !tt.ready {
+				g.Expect(notReady).NotTo(BeEmpty())
+				return
+			}
+			g.Expect(notReady).To(BeEmpty())
+			g.Expect(vpcSvc.vswitches).To(ConsistOf("vsw-master", "vsw-worker"))
+			g.Expect(clusterScope.ACKCluster.Status.VpcId).To(Equal("vpc-1"))
+			g.Expect(clusterScope.ACKCluster.Status.IntranetSlbId).To(Equal("lb-10.0.0.1"))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/aliyunerrors"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/vpc"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// machinesDeletionRequeueAfter is how long to wait for the ACKMachines of a deleted cluster to be gone.
+const machinesDeletionRequeueAfter = 15 * time.Second
+
+// errResourcesDeleting is returned while resources other resources depend on are still being
+// deleted asynchronously.
+var errResourcesDeleting = errors.New("resources are still being deleted")
+
+// reconcileDelete tears down the infrastructure owned by the cluster once all its ACKMachines
+// are gone, the load balancers, NAT gateways and elastic IP addresses first, then the security
+// groups, the vswitches and at last the vpc. Only resources tagged as owned by the cluster are
+// deleted, shared resources provided by the user are kept.
+func (r *ACKClusterReconciler) reconcileDelete(clusterScope *scope.ClusterScope) (ctrl.Result, error) {
+	clusterScope.Info("Reconciling ACKCluster delete")
+
+	machines := &infrav1.ACKMachineList{}
+	if err := r.List(context.TODO(), machines,
+		client.InNamespace(clusterScope.Namespace()),
+		client.MatchingLabels{clusterv1.ClusterLabelName: clusterScope.Name()},
+	); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list ACKMachines of cluster %s/%s", clusterScope.Namespace(), clusterScope.Name())
+	}
+	if len(machines.Items) > 0 {
+		clusterScope.Info("Waiting for ACKMachines to be deleted", "count", len(machines.Items))
+		return ctrl.Result{RequeueAfter: machinesDeletionRequeueAfter}, nil
+	}
+
+	if err := r.deleteInfrastructure(clusterScope); err != nil {
+		if aliyunerrors.IsDependencyViolation(err) || errors.Cause(err) == errResourcesDeleting {
+			// the dependent resources are still being released, requeueing without an error
+			// backs off exponentially on the rate limiter of the controller
+			clusterScope.Info("Resources are still in use, retrying", "reason", err.Error())
+			r.Recorder.Eventf(clusterScope.ACKCluster, corev1.EventTypeNormal, "DependencyViolation", "Waiting for dependent resources to be released: %v", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		r.Recorder.Eventf(clusterScope.ACKCluster, corev1.EventTypeWarning, "FailedDelete", "Failed to delete cluster infrastructure: %v", err)
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Eventf(clusterScope.ACKCluster, corev1.EventTypeNormal, "SuccessfulDelete", "Deleted cluster infrastructure")
+
+	// Cluster is deleted so remove the finalizer.
+	controllerutil.RemoveFinalizer(clusterScope.ACKCluster, scope.ClusterFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// deleteInfrastructure deletes the resources owned by the cluster in dependency order. The
+// NAT gateways are deleted asynchronously and keep their elastic IP addresses bound until they
+// are gone, so the elastic IP addresses are only released after that.
+func (r *ACKClusterReconciler) deleteInfrastructure(clusterScope *scope.ClusterScope) error {
+	owned := infrav1.Tags{infrav1.ClusterTagKey(clusterScope.Namespace(), clusterScope.Name()): string(infrav1.ResourceLifecycleOwned)}
+	slbSvc := r.getSLBService(clusterScope)
+	vpcSvc := r.getVPCService(clusterScope)
+	ecsSvc := r.getECSService(clusterScope)
+
+	loadBalancers, err := slbSvc.TaggedLoadBalancers(owned)
+	if err != nil {
+		return err
+	}
+	for _, id := range loadBalancers {
+		if err := slbSvc.DeleteLoadBalancer(id); err != nil {
+			return err
+		}
+	}
+
+	for _, step := range []struct {
+		resourceType string
+		list         func(string, infrav1.Tags) ([]string, error)
+		delete       func(string) error
+		// waitDeleted lists the resources again after deleting them and waits for them to be gone
+		waitDeleted bool
+	}{
+		{vpc.ResourceTypeNATGateway, vpcSvc.TaggedResources, vpcSvc.DeleteNatGateway, true},
+		{vpc.ResourceTypeEIP, vpcSvc.TaggedResources, vpcSvc.ReleaseEIP, false},
+		{ecs.ResourceTypeSecurityGroup, ecsSvc.TaggedResources, ecsSvc.DeleteSecurityGroup, false},
+		{vpc.ResourceTypeVSwitch, vpcSvc.TaggedResources, vpcSvc.DeleteVSwitch, false},
+		{vpc.ResourceTypeVPC, vpcSvc.TaggedResources, vpcSvc.DeleteVPC, false},
+	} {
+		ids, err := step.list(step.resourceType, owned)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := step.delete(id); err != nil {
+				return err
+			}
+		}
+		if step.waitDeleted && len(ids) > 0 {
+			remaining, err := step.list(step.resourceType, owned)
+			if err != nil {
+				return err
+			}
+			if len(remaining) > 0 {
+				return errors.Wrapf(errResourcesDeleting, "%s %v", step.resourceType, remaining)
+			}
+		}
+	}
+	return nil
+}
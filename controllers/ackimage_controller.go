@@ -0,0 +1,199 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/cluster-api/util/patch"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// imageCopyRequeueAfter is the interval the pending image copies are checked at
+	imageCopyRequeueAfter = 30 * time.Second
+)
+
+// ACKImageReconciler reconciles a ACKImage object
+type ACKImageReconciler struct {
+	client.Client
+	Log               logr.Logger
+	Scheme            *runtime.Scheme
+	Recorder          record.EventRecorder
+	ecsServiceFactory func(scope.ECSScope) services.ECSImageInterface
+}
+
+func (r *ACKImageReconciler) getECSService(scope scope.ECSScope) services.ECSImageInterface {
+	if r.ecsServiceFactory != nil {
+		return r.ecsServiceFactory(scope)
+	}
+	return ecs.NewService(scope)
+}
+
+// +kubebuilder:rbac:groups=ack.cluster.k8s.io,resources=ackimages,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ack.cluster.k8s.io,resources=ackimages/status,verbs=get;update;patch
+
+func (r *ACKImageReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
+	ctx := context.Background()
+	logger := r.Log.WithValues("ackimage", req.NamespacedName)
+
+	// fetch the ACKImage instance
+	ackImage := &infrav1.ACKImage{}
+	if err := r.Get(ctx, req.NamespacedName, ackImage); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// the copied images are kept when the ACKImage is deleted
+	if !ackImage.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	helper, err := patch.NewHelper(ackImage, r.Client)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to init patch helper")
+	}
+	defer func() {
+		if err := helper.Patch(ctx, ackImage); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
+
+	return r.reconcileNormal(logger, ackImage)
+}
+
+// reconcileNormal copies the source image into every target region without an available
+// copy and waits for the pending copies to become available.
+func (r *ACKImageReconciler) reconcileNormal(logger logr.Logger, ackImage *infrav1.ACKImage) (ctrl.Result, error) {
+	logger.Info("Reconciling ACKImage")
+	spec := ackImage.Spec
+	status := &ackImage.Status
+
+	sourceSvc, err := r.regionECSService(logger, spec.RegionId)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	sourceStatus, err := sourceSvc.ImageStatus(spec.ImageId)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if sourceStatus != ecs.ImageStatusAvailable {
+		logger.Info("Source image is not available yet", "status", sourceStatus)
+		status.Ready = false
+		return ctrl.Result{RequeueAfter: imageCopyRequeueAfter}, nil
+	}
+
+	images := map[string]string{spec.RegionId: spec.ImageId}
+	pending := map[string]string{}
+	for _, region := range spec.Regions {
+		if region == spec.RegionId {
+			continue
+		}
+
+		// a copy which is neither available nor pending is started
+		imageID, ok := status.PendingImages[region]
+		if !ok {
+			imageID, ok = status.Images[region]
+		}
+		if !ok {
+			if imageID, err = sourceSvc.CopyImage(spec.ImageId, region, imageSpec(ackImage)); err != nil {
+				r.Recorder.Eventf(ackImage, corev1.EventTypeWarning, "FailedCopyImage", "Failed to copy image %q to region %q: %v", spec.ImageId, region, err)
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Eventf(ackImage, corev1.EventTypeNormal, "SuccessfulCopyImage", "Copying image %q to region %q as %q", spec.ImageId, region, imageID)
+			pending[region] = imageID
+			continue
+		}
+
+		targetSvc, err := r.regionECSService(logger, region)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		imageStatus, err := targetSvc.ImageStatus(imageID)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		switch imageStatus {
+		case ecs.ImageStatusAvailable:
+			images[region] = imageID
+		case ecs.ImageStatusCreateFailed, "":
+			// the copy is retried on the next reconcile
+			r.Recorder.Eventf(ackImage, corev1.EventTypeWarning, "FailedCopyImage", "Copy %q of image %q to region %q failed", imageID, spec.ImageId, region)
+		default:
+			pending[region] = imageID
+		}
+	}
+
+	status.Images = images
+	status.PendingImages = pending
+	status.Ready = len(pending) == 0 && len(images) == len(uniqueRegions(spec))
+	if !status.Ready {
+		logger.Info("Waiting for image copies", "pending", pending)
+		return ctrl.Result{RequeueAfter: imageCopyRequeueAfter}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *ACKImageReconciler) regionECSService(logger logr.Logger, region string) (services.ECSImageInterface, error) {
+	regionScope, err := scope.NewRegionScope(scope.RegionScopeParams{Logger: logger, Region: region})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create region scope for %q", region)
+	}
+	return r.getECSService(regionScope), nil
+}
+
+// imageSpec returns the name, description and tags of the image copies.
+func imageSpec(ackImage *infrav1.ACKImage) *ecs.ImageSpec {
+	spec := &ecs.ImageSpec{
+		Name:        ackImage.Spec.ImageName,
+		Description: ackImage.Spec.Description,
+		Tags:        ackImage.Spec.Tags,
+	}
+	if spec.Name == "" {
+		spec.Name = ackImage.Spec.ImageId
+	}
+	return spec
+}
+
+// uniqueRegions returns the source and target regions without duplicates.
+func uniqueRegions(spec infrav1.ACKImageSpec) map[string]bool {
+	regions := map[string]bool{spec.RegionId: true}
+	for _, region := range spec.Regions {
+		regions[region] = true
+	}
+	return regions
+}
+
+func (r *ACKImageReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.ACKImage{}).
+		Complete(r)
+}
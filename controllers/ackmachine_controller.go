@@ -18,11 +18,24 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/aliyunerrors"
 	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
 	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services"
 	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/resourcemanager"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/vpc"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/util/conditions"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -31,32 +44,67 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha3"
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
+// chargeTypeConversionRequeueAfter is how long to wait for a PrePaid instance to become PostPaid.
+const chargeTypeConversionRequeueAfter = 10 * time.Second
+
+// detachedResourcesRequeueAfter is how long to wait for the instance to be released before
+// releasing its elastic IP address and network interfaces.
+const detachedResourcesRequeueAfter = 10 * time.Second
+
 // ACKMachineReconciler reconciles a ACKMachine object
 type ACKMachineReconciler struct {
 	client.Client
-	Log               logr.Logger
-	Scheme            *runtime.Scheme
-	Recorder          record.EventRecorder
-	ecsServiceFactory func() services.ECSMachineInterface
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// InstanceCache is the shared cache the instances are read from, the instances are
+	// described on every reconcile without one
+	InstanceCache     *ecs.InstanceCache
+	ecsServiceFactory func(*scope.ClusterScope) services.ECSMachineInterface
+	vpcServiceFactory func(*scope.ClusterScope) services.VPCInterface
+	// resourceManagerServiceFactory creates the service checking resource groups
+	resourceManagerServiceFactory func(*scope.ClusterScope) services.ResourceManagerInterface
 	//secretsManagerServiceFactory func(*scope.ClusterScope) services.SecretsManagerInterface
 }
 
-func (r *ACKMachineReconciler) getECSService() services.ECSMachineInterface {
+func (r *ACKMachineReconciler) getECSService(scope *scope.ClusterScope) services.ECSMachineInterface {
 	if r.ecsServiceFactory != nil {
-		return r.ecsServiceFactory()
+		return r.ecsServiceFactory(scope)
+	}
+	return ecs.NewCachedService(scope, r.InstanceCache)
+}
+
+func (r *ACKMachineReconciler) getVPCService(scope *scope.ClusterScope) services.VPCInterface {
+	if r.vpcServiceFactory != nil {
+		return r.vpcServiceFactory(scope)
+	}
+	return vpc.NewService(scope)
+}
+
+func (r *ACKMachineReconciler) getResourceManagerService(scope *scope.ClusterScope) services.ResourceManagerInterface {
+	if r.resourceManagerServiceFactory != nil {
+		return r.resourceManagerServiceFactory(scope)
 	}
-	return ecs.NewService()
+	return resourcemanager.NewService(scope)
 }
 
 // +kubebuilder:rbac:groups=ack.cluster.k8s.io,resources=ackmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ack.cluster.k8s.io,resources=ackmachines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ack.cluster.k8s.io,resources=ackimages,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 
-func (r *ACKMachineReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+func (r *ACKMachineReconciler) Reconcile(req ctrl.Request) (_ ctrl.Result, reterr error) {
 	ctx := context.Background()
 	logger := r.Log.WithValues("ackmachine", req.NamespacedName)
 
@@ -70,7 +118,8 @@ func (r *ACKMachineReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, err
 	}
 
-	machine, err := ecs.GetOwnerMachine(ackMachine.ObjectMeta)
+	// fetch the owner Machine (cluster-api)
+	machine, err := util.GetOwnerMachine(ctx, r.Client, ackMachine.ObjectMeta)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -79,36 +128,230 @@ func (r *ACKMachineReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error)
 		return ctrl.Result{}, nil
 	}
 
+	logger = logger.WithValues("machine", machine.Name)
+
 	// fetch the cluster-api Cluster
 	cluster, err := util.GetClusterFromMetadata(ctx, r.Client, machine.ObjectMeta)
 	if err != nil {
-		logger.Info("Machine is missing cluster label or cluster does not exist")
-		return ctrl.Result{}, nil
+		if errors.Cause(err) == util.ErrNoCluster || apierrors.IsNotFound(errors.Cause(err)) {
+			logger.Info("Machine is missing cluster label or cluster does not exist")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
 	}
 	// whether ackMachine or cluster is marked as paused
-	if isPaused(ackMachine) || isPaused(cluster) {
+	if util.IsPaused(cluster, ackMachine) {
 		logger.Info("ACKMachine or linked Cluster is marked as paused. Won't reconcile")
 		return ctrl.Result{}, nil
 	}
 
-	// fetch aws cluster
+	logger = logger.WithValues("cluster", cluster.Name)
+
+	// fetch the ACKCluster referenced by the Cluster's infrastructureRef
+	if cluster.Spec.InfrastructureRef == nil {
+		logger.Info("Cluster has no infrastructureRef yet")
+		return ctrl.Result{}, nil
+	}
+	ackCluster := &infrav1.ACKCluster{}
+	ackClusterName := client.ObjectKey{
+		Namespace: ackMachine.Namespace,
+		Name:      cluster.Spec.InfrastructureRef.Name,
+	}
+	if err := r.Client.Get(ctx, ackClusterName, ackCluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("ACKCluster is not available yet")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	logger = logger.WithValues("ackCluster", ackCluster.Name)
+
+	// create the cluster scope, it is only read here so it is never closed
+	clusterScope, err := scope.NewClusterScope(&scope.ClusterScopeParams{
+		Client:     r.Client,
+		Logger:     logger,
+		Cluster:    cluster,
+		ACKCluster: ackCluster,
+	})
+	if err != nil {
+		return ctrl.Result{}, errors.Errorf("failed to create cluster scope: %+v", err)
+	}
+
+	// create the machine scope
+	machineScope, err := scope.NewMachineScope(scope.MachineScopeParams{
+		Client:     r.Client,
+		Logger:     logger,
+		Cluster:    cluster,
+		Machine:    machine,
+		ACKCluster: ackCluster,
+		ACKMachine: ackMachine,
+	})
+	if err != nil {
+		return ctrl.Result{}, errors.Errorf("failed to create machine scope: %+v", err)
+	}
+
+	// always close the scope when exiting this function so we can persist any ACKMachine changes.
+	defer func() {
+		if err := machineScope.Close(); err != nil && reterr == nil {
+			reterr = err
+		}
+	}()
 
 	// Handle deleted machines
+	if !ackMachine.ObjectMeta.DeletionTimestamp.IsZero() {
+		result, err := r.reconcileDelete(machineScope, clusterScope)
+		return r.handleReconcileError(machineScope, capierrors.DeleteMachineError, result, err)
+	}
 
 	// Handle not-deleted machines
-	return r.reconcileNormals()
+	result, err := r.reconcileNormals(machineScope, clusterScope)
+	reason := capierrors.UpdateMachineError
+	if machineScope.GetProviderID() == "" {
+		reason = capierrors.CreateMachineError
+	}
+	return r.handleReconcileError(machineScope, reason, result, err)
+}
 
-	return ctrl.Result{}, nil
+// handleReconcileError requeues the ACKMachine with backoff on transient errors of the aliyun
+// api, and sets the failure reason and message on terminal errors which retrying can't fix, the
+// owner Machine is then remediated. A deleted ACKMachine keeps being retried so that its
+// finalizer is removed once the problem has been fixed.
+func (r *ACKMachineReconciler) handleReconcileError(machineScope *scope.MachineScope, operation capierrors.MachineStatusError, result ctrl.Result, err error) (ctrl.Result, error) {
+	if err == nil {
+		return result, nil
+	}
+	if aliyunerrors.IsRetryable(err) {
+		// requeueing without an error backs off exponentially on the rate limiter of the controller
+		machineScope.Info("Transient error reconciling ACKMachine, requeueing", "error", err.Error())
+		return ctrl.Result{Requeue: true}, nil
+	}
+	if reason, terminal := aliyunerrors.MachineStatusError(err, operation); terminal {
+		machineScope.SetFailureReason(reason)
+		machineScope.SetFailureMessage(err)
+		r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "ReconcileFailed", "Reconciling ACKMachine failed: %v", err)
+		if operation == capierrors.DeleteMachineError {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	return result, err
 }
 
 func (r *ACKMachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&infrav1.ACKMachine{}).
-		Complete(r)
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&infrav1.ACKMachine{})
+
+	// reconcile the ACKMachines whose cached instances changed state
+	if r.InstanceCache != nil {
+		if err := mgr.GetFieldIndexer().IndexField(&infrav1.ACKMachine{}, instanceIDField, indexInstanceID); err != nil {
+			return err
+		}
+		events := make(chan event.GenericEvent)
+		r.InstanceCache.OnStateChange(func(change ecs.InstanceStateChange) {
+			r.enqueueInstanceMachines(events, change)
+		})
+		if err := mgr.Add(r.InstanceCache); err != nil {
+			return err
+		}
+		builder = builder.Watches(&source.Channel{Source: events}, &handler.EnqueueRequestForObject{})
+	}
+
+	return builder.Complete(r)
+}
+
+// instanceIDField indexes the ACKMachines by the instance id of their provider id.
+const instanceIDField = "spec.providerID.instanceID"
+
+func indexInstanceID(o runtime.Object) []string {
+	ackMachine, ok := o.(*infrav1.ACKMachine)
+	if !ok || ackMachine.Spec.ProviderID == nil {
+		return nil
+	}
+	providerID, err := noderefutil.NewProviderID(*ackMachine.Spec.ProviderID)
+	if err != nil {
+		return nil
+	}
+	return []string{providerID.ID()}
+}
+
+// enqueueInstanceMachines sends a reconcile event for the ACKMachines of the instance.
+func (r *ACKMachineReconciler) enqueueInstanceMachines(events chan<- event.GenericEvent, change ecs.InstanceStateChange) {
+	machines := &infrav1.ACKMachineList{}
+	if err := r.List(context.TODO(), machines, client.MatchingFields{instanceIDField: change.InstanceID}); err != nil {
+		r.Log.Error(err, "Failed to list ACKMachines of instance", "instance-id", change.InstanceID)
+		return
+	}
+	for i := range machines.Items {
+		ackMachine := &machines.Items[i]
+		r.Log.V(2).Info("Instance changed state", "ackmachine", ackMachine.Namespace+"/"+ackMachine.Name,
+			"instance-id", change.InstanceID, "previous", change.Previous, "current", change.Current)
+		events <- event.GenericEvent{Meta: ackMachine, Object: ackMachine}
+	}
 }
 
-func (r *ACKMachineReconciler) reconcileDeleted() {
+func (r *ACKMachineReconciler) reconcileDelete(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (ctrl.Result, error) {
+	machineScope.Info("Handling deleted ACKMachine")
+
+	ecsSvc := r.getECSService(clusterScope)
+
+	instance, err := r.findInstance(machineScope, ecsSvc)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if instance == nil {
+		// The machine was never created or was deleted by some other entity
+		machineScope.V(3).Info("Unable to locate ECS instance by ID")
+		r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "NoInstanceFound", "Unable to find matching ECS instance")
 
+		// the elastic IP address and network interfaces are detached once the instance is gone
+		if err := r.releaseEIP(machineScope, r.getVPCService(clusterScope)); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.deleteNetworkInterfaces(machineScope, ecsSvc); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		controllerutil.RemoveFinalizer(machineScope.ACKMachine, infrav1.MachineFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	// PrePaid instances can not be released before they expire, convert them to PostPaid first
+	// and wait for the new charge type to be observed before terminating the instance.
+	if instance.InstanceChargeType == string(infrav1.InstanceChargeTypePrePaid) {
+		// the conversion has been requested already, only poll the charge type
+		if conditions.GetReason(machineScope.ACKMachine, infrav1.InstanceDeletableCondition) == infrav1.PrePaidConversionRequestedReason {
+			machineScope.V(2).Info("Waiting for PrePaid instance to become PostPaid", "instance-id", instance.Id)
+			return ctrl.Result{RequeueAfter: chargeTypeConversionRequeueAfter}, nil
+		}
+		if err := ecsSvc.ConvertToPostPaid(instance.Id); err != nil {
+			conditions.MarkFalse(machineScope.ACKMachine, infrav1.InstanceDeletableCondition, infrav1.PrePaidConversionFailedReason,
+				"PrePaid instance %s can not be converted to PostPaid, release it manually or retry later: %v", instance.Id, err)
+			r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "FailedConvertToPostPaid", "Failed to convert PrePaid instance %q to PostPaid: %v", instance.Id, err)
+			return ctrl.Result{}, err
+		}
+		conditions.MarkFalse(machineScope.ACKMachine, infrav1.InstanceDeletableCondition, infrav1.PrePaidConversionRequestedReason,
+			"Waiting for PrePaid instance %s to become PostPaid", instance.Id)
+		r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeNormal, "SuccessfulConvertToPostPaid", "Converted PrePaid instance %q to PostPaid", instance.Id)
+		return ctrl.Result{RequeueAfter: chargeTypeConversionRequeueAfter}, nil
+	}
+	conditions.MarkTrue(machineScope.ACKMachine, infrav1.InstanceDeletableCondition)
+
+	if err := ecsSvc.TerminateInstance(instance.Id); err != nil {
+		r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "FailedTerminate", "Failed to terminate instance %q: %v", instance.Id, err)
+		return ctrl.Result{}, errors.Wrap(err, "failed to terminate instance")
+	}
+	r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeNormal, "SuccessfulTerminate", "Terminated instance %q", instance.Id)
+
+	// allocated elastic IP addresses and created network interfaces can only be released once the instance is gone
+	if hasDetachableResources(machineScope) {
+		return ctrl.Result{RequeueAfter: detachedResourcesRequeueAfter}, nil
+	}
+
+	// Instance is deleted so remove the finalizer.
+	controllerutil.RemoveFinalizer(machineScope.ACKMachine, infrav1.MachineFinalizer)
+	return ctrl.Result{}, nil
 }
 
 func (r *ACKMachineReconciler) reconcileNormals(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (ctrl.Result, error) {
@@ -118,6 +361,11 @@ func (r *ACKMachineReconciler) reconcileNormals(machineScope *scope.MachineScope
 	if machineScope.HasFailed() {
 		return ctrl.Result{}, nil
 	}
+	// inherit the region from the ACKCluster
+	if machineScope.ACKMachine.Spec.RegionId == "" {
+		machineScope.ACKMachine.Spec.RegionId = clusterScope.ACKCluster.Spec.RegionId
+	}
+
 	// add default Finalizer if not exits
 	controllerutil.AddFinalizer(machineScope.ACKMachine, infrav1.MachineFinalizer)
 	// todo {Register the finalizer immediately to avoid orphaning ACK resources on delete}
@@ -138,27 +386,54 @@ func (r *ACKMachineReconciler) reconcileNormals(machineScope *scope.MachineScope
 		return ctrl.Result{}, nil
 	}
 
-	ecsSvc := r.getECSService()
+	ecsSvc := r.getECSService(clusterScope)
+
+	// pick an instance type with stock, a zone and a vswitch before the instance is created
+	if machineScope.GetProviderID() == "" {
+		// the resource group of the ACKCluster is checked by the cluster controller
+		if id := machineScope.ACKMachine.Spec.ResourceGroupId; id != "" {
+			if err := r.getResourceManagerService(clusterScope).CheckResourceGroup(id); err != nil {
+				r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "FailedResourceGroup", "Failed to check resource group: %v", err)
+				return ctrl.Result{}, err
+			}
+		}
+		if err := r.reconcileImage(machineScope, ecsSvc); err != nil {
+			r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "FailedResolveImage", "Failed to resolve image: %v", err)
+			return ctrl.Result{}, err
+		}
+		zones, err := r.reconcileInstanceType(machineScope, clusterScope, ecsSvc)
+		if err != nil {
+			r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "FailedSelectInstanceType", "Failed to select an instance type with stock: %v", err)
+			return ctrl.Result{}, err
+		}
+		if err := r.reconcilePlacement(machineScope, clusterScope, zones); err != nil {
+			r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "FailedPlacement", "Failed to place ACKMachine: %v", err)
+			return ctrl.Result{}, err
+		}
+	}
 
 	// get or create ecs instance
-	instance, err := r.getOrCreate(machineScope, &ecsSvc)
+	instance, err := r.getOrCreate(machineScope, ecsSvc)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	// Set an failure message if we couldn't find the instance.
 	if instance == nil {
-		machineScope.Info("EC2 instance cannot be found")
+		machineScope.Info("ECS instance cannot be found")
 		machineScope.SetFailureReason(capierrors.UpdateMachineError)
-		machineScope.SetFailureMessage(errors.New("EC2 instance cannot be found"))
+		machineScope.SetFailureMessage(errors.New("ECS instance cannot be found"))
 		return ctrl.Result{}, nil
 	}
 
 	// Make sure Spec.ProviderID is always set.
-	machineScope.SetProviderID(fmt.Sprintf("ack:////%s", instance.ID))
+	machineScope.SetProviderID(fmt.Sprintf("ack:////%s", instance.Id))
 
 	existingInstanceState := machineScope.GetInstanceState()
 	machineScope.SetInstanceState(instance.State)
+	machineScope.SetPlacement(instance.InstanceType, instance.ZoneId)
+	persistPlacement(machineScope, instance)
+	machineScope.SetExpiredTime(instance.ExpiredTime)
 
 	// Proceed to reconcile the AckMachine state.
 	if existingInstanceState == nil || *existingInstanceState != instance.State {
@@ -167,34 +442,404 @@ func (r *ACKMachineReconciler) reconcileNormals(machineScope *scope.MachineScope
 
 	// according to instance state to update ackMachine Status
 	switch instance.State {
-	case infrav1.InstanceStatePending, infrav1.InstanceStateStopping, infrav1.InstanceStateStopped:
+	case infrav1.InstanceStatePending, infrav1.InstanceStateStarting, infrav1.InstanceStateStopping, infrav1.InstanceStateStopped:
 		machineScope.SetNotReady()
 	case infrav1.InstanceStateRunning:
 		machineScope.SetReady()
-	case infrav1.InstanceStateShuttingDown, infrav1.InstanceStateTerminated:
-		machineScope.SetNotReady()
-		machineScope.Info("Unexpected EC2 instance termination", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
-		r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "InstanceUnexpectedTermination", "Unexpected EC2 instance termination")
 	default:
 		machineScope.SetNotReady()
-		machineScope.Info("EC2 instance state is undefined", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
-		r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "InstanceUnhandledState", "EC2 instance state is undefined")
+		machineScope.Info("ECS instance state is undefined", "state", instance.State, "instance-id", *machineScope.GetInstanceID())
+		r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "InstanceUnhandledState", "ECS instance state is undefined")
 		machineScope.SetFailureReason(capierrors.UpdateMachineError)
-		machineScope.SetFailureMessage(errors.Errorf("EC2 instance state %q is undefined", instance.State))
+		machineScope.SetFailureMessage(errors.Errorf("ECS instance state %q is undefined", instance.State))
+	}
+
+	// a preempted spot instance can not come back, fail the machine so that it is remediated
+	if instance.IsSpot() {
+		r.reconcileSpotInterruption(machineScope, instance)
 	}
 
-	// tasks that can take place during all known instance states, e.g. ensure tags
+	// tasks that can take place during all known instance states
+	volumes, err := ecsSvc.GetInstanceVolumes(instance.Id)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	machineScope.SetVolumes(volumes)
+
+	// disks can only be resized online while the instance is running
+	if instance.State == infrav1.InstanceStateRunning {
+		if err := r.reconcileVolumeSizes(machineScope, ecsSvc); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
 	// tasks that can only take place during operational instance states
 	// e.g. slb, security groups
+	if instance.State == infrav1.InstanceStateRunning || instance.State == infrav1.InstanceStateStopped {
+		if err := r.reconcileNetworkInterfaces(machineScope, ecsSvc, instance); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.reconcileEIP(machineScope, r.getVPCService(clusterScope), instance); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	machineScope.SetAddresses(instanceAddresses(instance, machineScope.ACKMachine.Status.EIP))
+
+	if err := r.reconcileTags(machineScope, ecsSvc, r.getVPCService(clusterScope), instance); err != nil {
+		r.Recorder.Eventf(machineScope.ACKMachine, corev1.EventTypeWarning, "FailedTagResources", "Failed to tag resources: %v", err)
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
-func (r *ACKMachineReconciler) getOrCreate(scope *scope.MachineScope, ecsSvc *services.ECSMachineInterface) (*infrav1.Instance, error) {
-	// first to get
-	findOne, err := r.findInstance()
+// reconcileTags applies the tags of the ACKMachine to the instance, the disks created with it and the network
+// interfaces and elastic IP address created for it, and corrects them when they drift.
+func (r *ACKMachineReconciler) reconcileTags(scope *scope.MachineScope, ecsSvc services.ECSMachineInterface, vpcSvc services.VPCInterface, instance *infrav1.Instance) error {
+	additional := scope.AdditionalTags()
+	removed, err := removedTags(scope.ACKMachine, additional)
+	if err != nil {
+		return err
+	}
+	tags := scope.Tags()
+
+	if err := ecsSvc.EnsureTags(ecs.ResourceTypeInstance, []string{instance.Id}, tags, removed); err != nil {
+		return err
+	}
+
+	// only the disks created with the instance are owned, not the ones attached later
+	var disks []string
+	systemVolume, dataVolumes := specVolumes(scope.ACKMachine)
+	if systemVolume != nil {
+		disks = append(disks, systemVolume.ID)
+	}
+	for _, volume := range dataVolumes {
+		disks = append(disks, volume.ID)
+	}
+	if err := ecsSvc.EnsureTags(ecs.ResourceTypeDisk, disks, tags, removed); err != nil {
+		return err
+	}
+
+	var networkInterfaces []string
+	for _, nic := range scope.ACKMachine.Status.NetworkInterfaces {
+		if nic.Created {
+			networkInterfaces = append(networkInterfaces, nic.ID)
+		}
+	}
+	if err := ecsSvc.EnsureTags(ecs.ResourceTypeENI, networkInterfaces, tags, removed); err != nil {
+		return err
+	}
+
+	if eip := scope.ACKMachine.Status.EIP; eip != nil && eip.Allocated {
+		if err := vpcSvc.EnsureTags(vpc.ResourceTypeEIP, []string{eip.AllocationId}, tags, removed); err != nil {
+			return err
+		}
+	}
+
+	return setLastAppliedTags(scope.ACKMachine, additional)
+}
+
+// specVolumes returns the system disk and the data disks of the instance which have been created
+// from the spec, the data disks in the order of the spec. Disks attached to the instance later,
+// e.g. persistent volumes, are attached after them and are not returned.
+func specVolumes(ackMachine *infrav1.ACKMachine) (*infrav1.Volume, []*infrav1.Volume) {
+	var systemVolume *infrav1.Volume
+	var dataVolumes []*infrav1.Volume
+	for i := range ackMachine.Status.Volumes {
+		volume := &ackMachine.Status.Volumes[i]
+		if volume.Type == infrav1.VolumeTypeSystem {
+			systemVolume = volume
+		} else {
+			dataVolumes = append(dataVolumes, volume)
+		}
+	}
+	sort.Slice(dataVolumes, func(i, j int) bool {
+		return dataVolumes[i].Device < dataVolumes[j].Device
+	})
+	if n := len(ackMachine.Spec.MachineVolumeSpec.DataDisks); len(dataVolumes) > n {
+		dataVolumes = dataVolumes[:n]
+	}
+	return systemVolume, dataVolumes
+}
+
+// reconcileVolumeSizes grows the disks of the instance online when their size has been increased in the spec.
+// Data disks are matched to the spec by device name, they are attached in the order of the spec.
+func (r *ACKMachineReconciler) reconcileVolumeSizes(scope *scope.MachineScope, ecsSvc services.ECSMachineInterface) error {
+	spec := scope.ACKMachine.Spec.MachineVolumeSpec
+	systemVolume, dataVolumes := specVolumes(scope.ACKMachine)
+
+	type resize struct {
+		volume *infrav1.Volume
+		size   string
+	}
+	var resizes []resize
+	if systemVolume != nil {
+		resizes = append(resizes, resize{volume: systemVolume, size: spec.SystemDisk.Size})
+	}
+	for i, volume := range dataVolumes {
+		if disk := spec.DataDisks[i]; disk != nil {
+			resizes = append(resizes, resize{volume: volume, size: disk.Size})
+		}
+	}
+
+	pending, err := pendingFilesystemResizes(scope.ACKMachine)
+	if err != nil {
+		return err
+	}
+
+	var resized, blocked []string
+	for _, rs := range resizes {
+		if rs.size == "" {
+			continue
+		}
+		size, err := strconv.ParseInt(rs.size, 10, 64)
+		if err != nil || size == rs.volume.Size {
+			continue
+		}
+		if size < rs.volume.Size {
+			// the webhook only rejects shrinking the spec of an existing ACKMachine, the disk may be larger from the start
+			if conditions.GetReason(scope.ACKMachine, infrav1.VolumesResizedCondition) != infrav1.VolumeShrinkNotSupportedReason {
+				r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeWarning, "FailedResizeDisk", "Disk %q is %d GiB and can not be shrunk to %d GiB", rs.volume.ID, rs.volume.Size, size)
+			}
+			conditions.MarkFalse(scope.ACKMachine, infrav1.VolumesResizedCondition, infrav1.VolumeShrinkNotSupportedReason,
+				"disk %s is %d GiB and can not be shrunk to %d GiB", rs.volume.ID, rs.volume.Size, size)
+			blocked = append(blocked, rs.volume.ID)
+			continue
+		}
+		if infrav1.DiskCategory(rs.volume.Category) == infrav1.DiskCategoryCloud {
+			conditions.MarkFalse(scope.ACKMachine, infrav1.VolumesResizedCondition, infrav1.VolumeResizeNotSupportedReason,
+				"disk %s of category %s can not be resized online", rs.volume.ID, rs.volume.Category)
+			blocked = append(blocked, rs.volume.ID)
+			continue
+		}
+		if err := ecsSvc.ResizeDisk(rs.volume.ID, size); err != nil {
+			conditions.MarkFalse(scope.ACKMachine, infrav1.VolumesResizedCondition, infrav1.VolumeResizeFailedReason, "%v", err)
+			r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeWarning, "FailedResizeDisk", "Failed to resize disk %q: %v", rs.volume.ID, err)
+			return err
+		}
+		r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeNormal, "SuccessfulResizeDisk", "Resized disk %q from %d GiB to %d GiB", rs.volume.ID, rs.volume.Size, size)
+		resized = append(resized, rs.volume.ID)
+		pending[rs.volume.ID] = size
+		rs.volume.Size = size
+	}
+	if len(resized) > 0 {
+		// the disks report their new size on the next reconcile, the annotation keeps the resize
+		// pending until the filesystems have been grown on the node
+		if err := setPendingFilesystemResizes(scope.ACKMachine, pending); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case len(blocked) > 0:
+		// the condition reports the disk which can not be resized
+	case len(pending) > 0:
+		conditions.MarkFalse(scope.ACKMachine, infrav1.VolumesResizedCondition, infrav1.FilesystemResizePendingReason,
+			"resized disks %s online, grow the partitions and filesystems on the node and remove the annotation %s",
+			strings.Join(describeResizes(pending), ", "), infrav1.FilesystemResizePendingAnnotation)
+	default:
+		// all disks have the size of the spec and no resize waits for the node
+		conditions.MarkTrue(scope.ACKMachine, infrav1.VolumesResizedCondition)
+	}
+	return nil
+}
+
+// reconcileEIP associates the elastic IP address of the spec with the instance, a new one is
+// allocated unless an existing allocation ID is given.
+func (r *ACKMachineReconciler) reconcileEIP(scope *scope.MachineScope, vpcSvc services.VPCInterface, instance *infrav1.Instance) error {
+	spec := scope.ACKMachine.Spec.EIP
+	if spec == nil {
+		return nil
+	}
+
+	status := scope.ACKMachine.Status.EIP
+	if instance.EipAddress.AllocationId != "" {
+		if status == nil || status.AllocationId != instance.EipAddress.AllocationId {
+			status = &infrav1.EIPStatus{AllocationId: instance.EipAddress.AllocationId}
+		}
+		status.IpAddress = instance.EipAddress.IpAddress
+		scope.SetEIP(status)
+		return nil
+	}
+
+	if status == nil || status.AllocationId == "" {
+		if spec.AllocationId != "" {
+			status = &infrav1.EIPStatus{AllocationId: spec.AllocationId}
+		} else {
+			allocated, err := vpcSvc.AllocateEIP(scope, spec)
+			if err != nil {
+				r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeWarning, "FailedAllocateEIP", "Failed to allocate elastic IP address: %v", err)
+				return err
+			}
+			r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeNormal, "SuccessfulAllocateEIP", "Allocated elastic IP address %q", allocated.AllocationId)
+			status = allocated
+		}
+		// record the allocation right away, so it is released even if the association fails
+		scope.SetEIP(status)
+	}
+
+	if err := vpcSvc.AssociateEIP(status.AllocationId, instance.Id); err != nil {
+		r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeWarning, "FailedAssociateEIP", "Failed to associate elastic IP address %q: %v", status.AllocationId, err)
+		return err
+	}
+	r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeNormal, "SuccessfulAssociateEIP", "Associated elastic IP address %q", status.AllocationId)
+	return nil
+}
+
+// releaseEIP releases the elastic IP address allocated for the ACKMachine, existing ones are left alone.
+func (r *ACKMachineReconciler) releaseEIP(scope *scope.MachineScope, vpcSvc services.VPCInterface) error {
+	eip := scope.ACKMachine.Status.EIP
+	if eip == nil || !eip.Allocated {
+		return nil
+	}
+
+	if err := vpcSvc.ReleaseEIP(eip.AllocationId); err != nil {
+		r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeWarning, "FailedReleaseEIP", "Failed to release elastic IP address %q: %v", eip.AllocationId, err)
+		return err
+	}
+	r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeNormal, "SuccessfulReleaseEIP", "Released elastic IP address %q", eip.AllocationId)
+	scope.SetEIP(nil)
+	return nil
+}
+
+// reconcileNetworkInterfaces creates the secondary network interfaces of the spec and attaches them to the instance.
+// The status keeps the network interfaces in the order of the spec.
+func (r *ACKMachineReconciler) reconcileNetworkInterfaces(scope *scope.MachineScope, ecsSvc services.ECSMachineInterface, instance *infrav1.Instance) error {
+	specs := scope.ACKMachine.Spec.NetworkInterfaces
+	if len(specs) == 0 {
+		return nil
+	}
+
+	statuses := scope.ACKMachine.Status.NetworkInterfaces
+	for i := len(statuses); i < len(specs); i++ {
+		if specs[i].ID != "" {
+			statuses = append(statuses, infrav1.NetworkInterfaceStatus{ID: specs[i].ID})
+			continue
+		}
+
+		// the network interface defaults to the vswitch and security groups of the instance
+		nic := specs[i]
+		if nic.VSwitchId == "" {
+			nic.VSwitchId = instance.VpcAttributes.VSwitchId
+		}
+		if nic.SecurityGroupId == "" && len(nic.SecurityGroupIds) == 0 {
+			nic.SecurityGroupIds = instance.SecurityGroupIDs
+		}
+		id, err := ecsSvc.CreateNetworkInterface(scope, &nic)
+		if err != nil {
+			r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeWarning, "FailedCreateNetworkInterface", "Failed to create network interface: %v", err)
+			return err
+		}
+		r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeNormal, "SuccessfulCreateNetworkInterface", "Created network interface %q", id)
+
+		// record the network interface right away, so it is deleted even if attaching it fails
+		statuses = append(statuses, infrav1.NetworkInterfaceStatus{ID: id, Created: true})
+		scope.SetNetworkInterfaces(statuses)
+	}
+	scope.SetNetworkInterfaces(statuses)
+
+	ids := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		ids = append(ids, status.ID)
+	}
+	observed, err := ecsSvc.DescribeNetworkInterfaces(ids)
 	if err != nil {
+		return err
+	}
+	byID := make(map[string]infrav1.NetworkInterfaceStatus, len(observed))
+	for _, nic := range observed {
+		byID[nic.ID] = nic
+	}
+
+	for i := range statuses {
+		status := &statuses[i]
+		nic, ok := byID[status.ID]
+		if !ok {
+			return errors.Errorf("network interface %q not found", status.ID)
+		}
+
+		switch nic.InstanceId {
+		case instance.Id:
+		case "":
+			if err := ecsSvc.AttachNetworkInterface(nic.ID, instance.Id); err != nil {
+				r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeWarning, "FailedAttachNetworkInterface", "Failed to attach network interface %q: %v", nic.ID, err)
+				return err
+			}
+			r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeNormal, "SuccessfulAttachNetworkInterface", "Attached network interface %q", nic.ID)
+			nic.InstanceId = instance.Id
+		default:
+			return errors.Errorf("network interface %q is attached to another instance %q", nic.ID, nic.InstanceId)
+		}
+
+		status.Name = nic.Name
+		status.PrivateIpAddress = nic.PrivateIpAddress
+		status.MacAddress = nic.MacAddress
+		status.InstanceId = nic.InstanceId
+	}
+	return nil
+}
+
+// deleteNetworkInterfaces deletes the network interfaces created for the ACKMachine, existing ones are left alone.
+func (r *ACKMachineReconciler) deleteNetworkInterfaces(scope *scope.MachineScope, ecsSvc services.ECSMachineInterface) error {
+	var remaining []infrav1.NetworkInterfaceStatus
+	for _, nic := range scope.ACKMachine.Status.NetworkInterfaces {
+		if !nic.Created {
+			continue
+		}
+		if err := ecsSvc.DeleteNetworkInterface(nic.ID); err != nil {
+			r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeWarning, "FailedDeleteNetworkInterface", "Failed to delete network interface %q: %v", nic.ID, err)
+			remaining = append(remaining, nic)
+			continue
+		}
+		r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeNormal, "SuccessfulDeleteNetworkInterface", "Deleted network interface %q", nic.ID)
+	}
+	scope.SetNetworkInterfaces(remaining)
+
+	if len(remaining) > 0 {
+		return errors.Errorf("failed to delete %d network interfaces", len(remaining))
+	}
+	return nil
+}
+
+// hasDetachableResources returns true if elastic IP addresses or network interfaces allocated for the
+// ACKMachine have to be released once the instance is gone.
+func hasDetachableResources(scope *scope.MachineScope) bool {
+	if eip := scope.ACKMachine.Status.EIP; eip != nil && eip.Allocated {
+		return true
+	}
+	for _, nic := range scope.ACKMachine.Status.NetworkInterfaces {
+		if nic.Created {
+			return true
+		}
+	}
+	return false
+}
 
+// instanceAddresses returns the private and public addresses of the instance.
+func instanceAddresses(instance *infrav1.Instance, eip *infrav1.EIPStatus) []clusterv1.MachineAddress {
+	var addresses []clusterv1.MachineAddress
+	for _, ip := range instance.VpcAttributes.PrivateIpAddress {
+		addresses = append(addresses, clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: ip})
+	}
+	for _, ip := range instance.InnerIpAddress {
+		addresses = append(addresses, clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: ip})
+	}
+	for _, ip := range instance.PublicIpAddress {
+		addresses = append(addresses, clusterv1.MachineAddress{Type: clusterv1.MachineExternalIP, Address: ip})
+	}
+	if eip != nil && eip.IpAddress != "" {
+		addresses = append(addresses, clusterv1.MachineAddress{Type: clusterv1.MachineExternalIP, Address: eip.IpAddress})
+	} else if instance.EipAddress.IpAddress != "" {
+		addresses = append(addresses, clusterv1.MachineAddress{Type: clusterv1.MachineExternalIP, Address: instance.EipAddress.IpAddress})
+	}
+	return addresses
+}
+
+func (r *ACKMachineReconciler) getOrCreate(scope *scope.MachineScope, ecsSvc services.ECSMachineInterface) (*infrav1.Instance, error) {
+	// first to get
+	findOne, err := r.findInstance(scope, ecsSvc)
+	if err != nil {
+		return nil, err
 	}
 	if findOne != nil {
 		return findOne, nil
@@ -202,19 +847,53 @@ func (r *ACKMachineReconciler) getOrCreate(scope *scope.MachineScope, ecsSvc *se
 
 	// Otherwise then create one
 	// get userData
-	userData, err := GetUserData()
+	userData, err := scope.GetRawBootstrapData()
 	if err != nil {
 		return nil, err
 	}
 
-	instance, err := (*ecsSvc).RunInstances()
+	instance, err := ecsSvc.CreateInstance(scope, userData, scope.ResourceGroupID(), scope.Tags())
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to create AWSMachine instance")
+		// the zone is chosen again on the next reconcile
+		if aliyunerrors.IsNoStock(err) && scope.ACKMachine.Spec.ZoneId == "" && scope.ACKMachine.Status.ZoneId != "" {
+			scope.Info("Zone is out of stock", "zone", scope.ACKMachine.Status.ZoneId)
+			scope.AddNoStockZone(scope.ACKMachine.Status.ZoneId)
+		}
+		return nil, errors.Wrapf(err, "failed to create ACKMachine instance")
 	}
 	return instance, nil
 }
 
-func (r *ACKMachineReconciler) findInstance() (*infrav1.Instance, error) {
+func (r *ACKMachineReconciler) findInstance(scope *scope.MachineScope, ecsSvc services.ECSMachineInterface) (*infrav1.Instance, error) {
+	// Parse the ProviderID, an ACKMachine without one has no instance yet.
+	if scope.GetProviderID() == "" {
+		return nil, nil
+	}
+	instanceID := scope.GetInstanceID()
+	if instanceID == nil {
+		return nil, errors.Errorf("failed to parse Spec.ProviderID %q", scope.GetProviderID())
+	}
+
 	// query ecs service to find instance
-	return nil, nil
+	instance, err := ecsSvc.InstanceIfExists(instanceID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query ACKMachine instance")
+	}
+	return instance, nil
+}
+
+// reconcileSpotInterruption marks the ACKMachine failed once its spot instance has been preempted.
+// A preempted spot instance is locked for recycling until it is released, which is the interruption
+// notice DescribeInstances reports. The owner Machine then becomes unhealthy and is replaced by the
+// MachineHealthCheck.
+func (r *ACKMachineReconciler) reconcileSpotInterruption(scope *scope.MachineScope, instance *infrav1.Instance) {
+	if !instance.IsRecycling() {
+		return
+	}
+
+	scope.Info("Spot instance is locked for recycling", "instance-id", instance.Id)
+	r.Recorder.Eventf(scope.ACKMachine, corev1.EventTypeWarning, "SpotInstanceRecycling", "ECS instance %s has been preempted and is locked for recycling", instance.Id)
+	scope.SetNotReady()
+	scope.SetFailureReason(capierrors.UpdateMachineError)
+	scope.SetFailureMessage(errors.Errorf("ECS instance %s has been preempted and is locked for recycling", instance.Id))
 }
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileImage resolves the image lookup or reference of an ACKMachine once and records the
+// image in status. The image of a machine of the same MachineSet is reused, so all machines
+// created from the same template run the same image even if a newer one is published meanwhile.
+func (r *ACKMachineReconciler) reconcileImage(machineScope *scope.MachineScope, ecsSvc services.ECSMachineInterface) error {
+	if machineScope.ACKMachine.Status.ImageId != "" {
+		return nil
+	}
+	if ref := machineScope.ACKMachine.Spec.ImageRef; ref != nil {
+		return r.reconcileImageRef(machineScope, ref)
+	}
+	lookup := machineScope.ACKMachine.Spec.ImageLookup
+	if lookup == nil {
+		return nil
+	}
+
+	imageID, err := r.machineSetImage(machineScope)
+	if err != nil {
+		return err
+	}
+	if imageID == "" {
+		if imageID, err = ecsSvc.LookupImage(lookup); err != nil {
+			return err
+		}
+	}
+
+	machineScope.Info("Resolved image", "image-id", imageID)
+	machineScope.SetImageID(imageID)
+	return nil
+}
+
+// reconcileImageRef records the copy of the referenced ACKImage in the region of the ACKMachine.
+func (r *ACKMachineReconciler) reconcileImageRef(machineScope *scope.MachineScope, ref *corev1.LocalObjectReference) error {
+	ackImage := &infrav1.ACKImage{}
+	key := client.ObjectKey{Namespace: machineScope.Namespace(), Name: ref.Name}
+	if err := r.Client.Get(context.TODO(), key, ackImage); err != nil {
+		return errors.Wrapf(err, "failed to get ACKImage %s", key)
+	}
+
+	region := machineScope.ACKMachine.Spec.RegionId
+	imageID, ok := ackImage.Status.Images[region]
+	if !ok {
+		return errors.Errorf("ACKImage %s is not available in region %q yet", key, region)
+	}
+
+	machineScope.Info("Resolved image", "image-id", imageID, "ackimage", ref.Name)
+	machineScope.SetImageID(imageID)
+	return nil
+}
+
+// machineSetImage returns the image resolved by another ACKMachine of the same MachineSet with
+// the same image lookup, or nothing if there is none.
+func (r *ACKMachineReconciler) machineSetImage(machineScope *scope.MachineScope) (string, error) {
+	machineSet, ok := machineScope.Machine.Labels[clusterv1.MachineSetLabelName]
+	if !ok {
+		return "", nil
+	}
+	ctx := context.TODO()
+
+	machines := &clusterv1.MachineList{}
+	selector := client.MatchingLabels{clusterv1.MachineSetLabelName: machineSet}
+	if err := r.Client.List(ctx, machines, client.InNamespace(machineScope.Namespace()), selector); err != nil {
+		return "", errors.Wrap(err, "failed to list Machines")
+	}
+
+	for _, machine := range machines.Items {
+		if machine.Name == machineScope.Machine.Name || machine.Spec.InfrastructureRef.Kind != "ACKMachine" {
+			continue
+		}
+		ackMachine := &infrav1.ACKMachine{}
+		key := client.ObjectKey{Namespace: machine.Namespace, Name: machine.Spec.InfrastructureRef.Name}
+		if err := r.Client.Get(ctx, key, ackMachine); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", errors.Wrapf(err, "failed to get ACKMachine %s", key)
+		}
+		if ackMachine.Status.ImageId != "" && reflect.DeepEqual(ackMachine.Spec.ImageLookup, machineScope.ACKMachine.Spec.ImageLookup) {
+			return ackMachine.Status.ImageId, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileInstanceType picks the first of the acceptable instance types of an ACKMachine
+// which has stock in one of its acceptable zones, and returns the zones with stock. No zones
+// are returned if the ACKMachine has neither alternative instance types nor zones.
+func (r *ACKMachineReconciler) reconcileInstanceType(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope, ecsSvc services.ECSMachineInterface) ([]string, error) {
+	spec := &machineScope.ACKMachine.Spec
+	if len(spec.InstanceTypes) == 0 && len(spec.ZoneIds) == 0 {
+		return nil, nil
+	}
+
+	var instanceTypes []string
+	if spec.InstanceType != "" {
+		instanceTypes = append(instanceTypes, spec.InstanceType)
+	}
+	instanceTypes = append(instanceTypes, spec.InstanceTypes...)
+	zones := acceptableZones(machineScope, clusterScope)
+
+	for _, instanceType := range instanceTypes {
+		available, err := ecsSvc.AvailableZones(machineScope, instanceType)
+		if err != nil {
+			return nil, err
+		}
+		if len(zones) > 0 {
+			available = intersectZones(zones, available)
+		}
+		if len(available) > 0 {
+			machineScope.Info("Selected instance type with stock", "instance-type", instanceType, "zones", available)
+			machineScope.SetPlacement(instanceType, "")
+			return available, nil
+		}
+	}
+	return nil, errors.Errorf("none of the instance types %v has stock in zones %v", instanceTypes, zones)
+}
+
+// acceptableZones returns the zones the instance of the ACKMachine may be created in, or
+// nothing if it may be created in any zone.
+func acceptableZones(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) []string {
+	spec := &machineScope.ACKMachine.Spec
+	if spec.ZoneId != "" {
+		return []string{spec.ZoneId}
+	}
+	if vswitchID := spec.MachineNetworkSpec.VSwitchId; vswitchID != "" {
+		_, vswitches := clusterVSwitches(machineScope, clusterScope)
+		for _, vswitch := range vswitches {
+			if vswitch.ID == vswitchID {
+				return []string{vswitch.ZoneId}
+			}
+		}
+	}
+	if failureDomain := machineScope.Machine.Spec.FailureDomain; failureDomain != nil && *failureDomain != "" {
+		return []string{*failureDomain}
+	}
+	if zones := excludeZones(spec.ZoneIds, machineScope.ACKMachine.Status.NoStockZones); len(zones) > 0 {
+		return zones
+	}
+	// all acceptable zones have been out of stock, try them again
+	return spec.ZoneIds
+}
+
+// excludeZones returns the zones which are not excluded, in the order of zones.
+func excludeZones(zones, excluded []string) []string {
+	var result []string
+	for _, zone := range zones {
+		found := false
+		for _, e := range excluded {
+			if zone == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, zone)
+		}
+	}
+	return result
+}
+
+// intersectZones returns the zones which are also available, in the order of zones.
+func intersectZones(zones, available []string) []string {
+	var result []string
+	for _, zone := range zones {
+		for _, a := range available {
+			if zone == a {
+				result = append(result, zone)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// clusterVSwitches returns the master vswitches of the ACKCluster for control plane machines
+// and the worker vswitches for the others.
+func clusterVSwitches(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope) (string, []infrav1.VSwitch) {
+	if machineScope.IsControlPlane() {
+		return "master", clusterScope.ACKCluster.Status.MasterVSwitches
+	}
+	return "worker", clusterScope.ACKCluster.Status.WorkerVSwitches
+}
+
+// reconcilePlacement picks the zone and vswitch of an ACKMachine without them from the master
+// vswitches of the ACKCluster for control plane machines and from the worker vswitches for the
+// others. The failure domain of the Machine is honoured, otherwise the machines of a
+// MachineDeployment are spread across the zones in round-robin order, skipping the zones which
+// have been out of stock. If zones are given the machine is only placed in one of them.
+// The choice is kept in the status until the instance has been created, so that it can be
+// made again if the zone is out of stock.
+func (r *ACKMachineReconciler) reconcilePlacement(machineScope *scope.MachineScope, clusterScope *scope.ClusterScope, zones []string) error {
+	spec := &machineScope.ACKMachine.Spec
+	network := &spec.MachineNetworkSpec
+	if spec.ZoneId != "" && network.VSwitchId != "" {
+		machineScope.SetZone(spec.ZoneId, network.VSwitchId)
+		return nil
+	}
+	// the launch template may provide the vswitch
+	if spec.LaunchTemplate != nil && spec.ZoneId == "" && network.VSwitchId == "" {
+		machineScope.SetZone("", "")
+		return nil
+	}
+
+	kind, vswitches := clusterVSwitches(machineScope, clusterScope)
+
+	// the zone of a given vswitch is known
+	if network.VSwitchId != "" {
+		zone := ""
+		for _, vswitch := range vswitches {
+			if vswitch.ID == network.VSwitchId {
+				zone = vswitch.ZoneId
+			}
+		}
+		machineScope.SetZone(zone, network.VSwitchId)
+		return nil
+	}
+
+	zone := spec.ZoneId
+	if failureDomain := machineScope.Machine.Spec.FailureDomain; failureDomain != nil && *failureDomain != "" {
+		if zone != "" && zone != *failureDomain {
+			return errors.Errorf("zone %q does not match the failure domain %q of the Machine", zone, *failureDomain)
+		}
+		zone = *failureDomain
+	}
+
+	if zone == "" {
+		usage, err := r.zoneUsage(machineScope)
+		if err != nil {
+			return err
+		}
+		if len(zones) > 0 {
+			vswitches = vswitchesInZones(vswitches, zones)
+		}
+		if inStock := vswitchesNotInZones(vswitches, machineScope.ACKMachine.Status.NoStockZones); len(inStock) > 0 {
+			vswitches = inStock
+		}
+		zone = leastUsedZone(vswitches, usage)
+	}
+
+	vswitch := pickVSwitch(vswitches, zone)
+	if vswitch == nil {
+		return errors.Errorf("no %s vswitch of ACKCluster %s available in zone %q", kind, clusterScope.ACKCluster.Name, zone)
+	}
+
+	if vswitch.ZoneId != machineScope.ACKMachine.Status.ZoneId || vswitch.ID != machineScope.ACKMachine.Status.VSwitchId {
+		machineScope.Info("Placing ACKMachine", "zone", vswitch.ZoneId, "vswitch", vswitch.ID)
+	}
+	machineScope.SetZone(vswitch.ZoneId, vswitch.ID)
+	return nil
+}
+
+// persistPlacement records the zone and vswitch of the created instance in the spec of an
+// ACKMachine the controller has placed.
+func persistPlacement(machineScope *scope.MachineScope, instance *infrav1.Instance) {
+	spec := &machineScope.ACKMachine.Spec
+	machineScope.SetZone(instance.ZoneId, instance.VpcAttributes.VSwitchId)
+	machineScope.ACKMachine.Status.NoStockZones = nil
+	if spec.LaunchTemplate != nil {
+		return
+	}
+	if spec.ZoneId == "" {
+		spec.ZoneId = instance.ZoneId
+	}
+	if spec.MachineNetworkSpec.VSwitchId == "" {
+		spec.MachineNetworkSpec.VSwitchId = instance.VpcAttributes.VSwitchId
+	}
+}
+
+// zoneUsage counts the ACKMachines per zone of the MachineDeployment the Machine belongs to,
+// or of the whole cluster for Machines which are not part of a MachineDeployment.
+func (r *ACKMachineReconciler) zoneUsage(machineScope *scope.MachineScope) (map[string]int, error) {
+	ctx := context.TODO()
+
+	selector := client.MatchingLabels{clusterv1.ClusterLabelName: machineScope.Cluster.Name}
+	if deployment, ok := machineScope.Machine.Labels[clusterv1.MachineDeploymentLabelName]; ok {
+		selector[clusterv1.MachineDeploymentLabelName] = deployment
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines, client.InNamespace(machineScope.Namespace()), selector); err != nil {
+		return nil, errors.Wrap(err, "failed to list Machines")
+	}
+
+	usage := map[string]int{}
+	for _, machine := range machines.Items {
+		if machine.Name == machineScope.Machine.Name || machine.Spec.InfrastructureRef.Kind != "ACKMachine" {
+			continue
+		}
+		ackMachine := &infrav1.ACKMachine{}
+		key := client.ObjectKey{Namespace: machine.Namespace, Name: machine.Spec.InfrastructureRef.Name}
+		if err := r.Client.Get(ctx, key, ackMachine); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get ACKMachine %s", key)
+		}
+		// the status holds the zone of machines which have not been created yet
+		if zone := ackMachine.Status.ZoneId; zone != "" {
+			usage[zone]++
+		} else if ackMachine.Spec.ZoneId != "" {
+			usage[ackMachine.Spec.ZoneId]++
+		}
+	}
+	return usage, nil
+}
+
+// leastUsedZone returns the zone of the vswitches with the fewest machines, ties are broken
+// by the zone name so the machines are placed in round-robin order.
+func leastUsedZone(vswitches []infrav1.VSwitch, usage map[string]int) string {
+	var zones []string
+	seen := map[string]bool{}
+	for _, vswitch := range vswitches {
+		if !seen[vswitch.ZoneId] {
+			seen[vswitch.ZoneId] = true
+			zones = append(zones, vswitch.ZoneId)
+		}
+	}
+	sort.Strings(zones)
+
+	var zone string
+	for _, z := range zones {
+		if zone == "" || usage[z] < usage[zone] {
+			zone = z
+		}
+	}
+	return zone
+}
+
+// vswitchesInZones returns the vswitches in one of the zones.
+func vswitchesInZones(vswitches []infrav1.VSwitch, zones []string) []infrav1.VSwitch {
+	var result []infrav1.VSwitch
+	for _, vswitch := range vswitches {
+		for _, zone := range zones {
+			if vswitch.ZoneId == zone {
+				result = append(result, vswitch)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// vswitchesNotInZones returns the vswitches in none of the zones.
+func vswitchesNotInZones(vswitches []infrav1.VSwitch, zones []string) []infrav1.VSwitch {
+	var result []infrav1.VSwitch
+	for _, vswitch := range vswitches {
+		if len(excludeZones([]string{vswitch.ZoneId}, zones)) > 0 {
+			result = append(result, vswitch)
+		}
+	}
+	return result
+}
+
+// pickVSwitch returns the vswitch of the zone with the most available IP addresses.
+func pickVSwitch(vswitches []infrav1.VSwitch, zone string) *infrav1.VSwitch {
+	var picked *infrav1.VSwitch
+	for i := range vswitches {
+		vswitch := &vswitches[i]
+		if vswitch.ZoneId != zone {
+			continue
+		}
+		if picked == nil || vswitch.AvailableIpAddressCount > picked.AvailableIpAddressCount {
+			picked = vswitch
+		}
+	}
+	return picked
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/gc"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/vpc"
+)
+
+var (
+	orphanedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ack_orphaned_resources",
+		Help: "Number of resources owned by a cluster which no longer exists or no longer referenced by an ACKMachine.",
+	}, []string{"region", "resource_type"})
+
+	orphanedResourcesDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ack_orphaned_resources_deleted_total",
+		Help: "Total number of orphaned resources deleted by the orphan collector.",
+	}, []string{"region", "resource_type"})
+
+	orphanedResourcesDeleteErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ack_orphaned_resources_delete_errors_total",
+		Help: "Total number of orphaned resources the orphan collector failed to delete.",
+	}, []string{"region", "resource_type"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanedResources, orphanedResourcesDeleted, orphanedResourcesDeleteErrors)
+}
+
+// OrphanCollector periodically deletes the resources tagged as owned by a cluster of the
+// provider which are left behind by crashes and partial failures, i.e. the resources of a
+// cluster which no longer exists and the instances and elastic IP addresses no ACKMachine
+// refers to. A resource is only deleted once it has been orphaned for the grace period.
+type OrphanCollector struct {
+	client.Client
+	Log logr.Logger
+	// Regions are scanned in addition to the regions of the existing ACKClusters, so the
+	// resources of deleted clusters are found in regions without any ACKCluster left.
+	Regions     []string
+	Interval    time.Duration
+	GracePeriod time.Duration
+	// DryRun only reports the orphaned resources instead of deleting them.
+	DryRun bool
+
+	gcServiceFactory func(region string) (services.GCInterface, error)
+	// orphanedSince records when a resource has been found orphaned first
+	orphanedSince map[string]time.Time
+}
+
+func (c *OrphanCollector) getGCService(region string) (services.GCInterface, error) {
+	if c.gcServiceFactory != nil {
+		return c.gcServiceFactory(region)
+	}
+	regionScope, err := scope.NewRegionScope(scope.RegionScopeParams{
+		Logger: c.Log,
+		Region: region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return gc.NewService(regionScope), nil
+}
+
+// SetupWithManager adds the collector to the manager, it only runs on the leader.
+func (c *OrphanCollector) SetupWithManager(mgr ctrl.Manager) error {
+	return mgr.Add(c)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (c *OrphanCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start collects the orphaned resources every interval until stop is closed.
+func (c *OrphanCollector) Start(stop <-chan struct{}) error {
+	c.Log.Info("Starting orphan collector", "interval", c.Interval, "grace-period", c.GracePeriod, "dry-run", c.DryRun)
+	c.orphanedSince = map[string]time.Time{}
+	wait.Until(func() {
+		if err := c.collect(context.Background()); err != nil {
+			c.Log.Error(err, "Failed to collect orphaned resources")
+		}
+	}, c.Interval, stop)
+	return nil
+}
+
+func (c *OrphanCollector) collect(ctx context.Context) error {
+	clusters := &clusterv1.ClusterList{}
+	if err := c.List(ctx, clusters); err != nil {
+		return err
+	}
+	ackClusters := &infrav1.ACKClusterList{}
+	if err := c.List(ctx, ackClusters); err != nil {
+		return err
+	}
+	ackMachines := &infrav1.ACKMachineList{}
+	if err := c.List(ctx, ackMachines); err != nil {
+		return err
+	}
+
+	liveClusters := sets.NewString()
+	for _, cluster := range clusters.Items {
+		liveClusters.Insert(cluster.Namespace + "/" + cluster.Name)
+	}
+	regions := sets.NewString(c.Regions...)
+	for _, ackCluster := range ackClusters.Items {
+		liveClusters.Insert(ackCluster.Namespace + "/" + ackCluster.Name)
+		if ackCluster.Spec.RegionId != "" {
+			regions.Insert(ackCluster.Spec.RegionId)
+		}
+	}
+	referenced := sets.NewString()
+	for _, ackMachine := range ackMachines.Items {
+		if ackMachine.Spec.ProviderID != nil {
+			if providerID, err := noderefutil.NewProviderID(*ackMachine.Spec.ProviderID); err == nil {
+				referenced.Insert(providerID.ID())
+			}
+		}
+		if eip := ackMachine.Status.EIP; eip != nil && eip.AllocationId != "" {
+			referenced.Insert(eip.AllocationId)
+		}
+		for _, volume := range ackMachine.Status.Volumes {
+			referenced.Insert(volume.ID)
+		}
+	}
+
+	now := time.Now()
+	seen := sets.NewString()
+	for _, region := range regions.List() {
+		log := c.Log.WithValues("region", region)
+		gcSvc, err := c.getGCService(region)
+		if err != nil {
+			log.Error(err, "Failed to create the services of the region")
+			continue
+		}
+		resources, err := gcSvc.OwnedResources()
+		if err != nil {
+			log.Error(err, "Failed to list owned resources")
+			continue
+		}
+
+		counts := map[string]float64{}
+		for _, resourceType := range []string{ecs.ResourceTypeInstance, ecs.ResourceTypeDisk, ecs.ResourceTypeSecurityGroup, vpc.ResourceTypeEIP} {
+			counts[resourceType] = 0
+		}
+		for _, resource := range resources {
+			if !isOrphaned(resource, liveClusters, referenced) {
+				continue
+			}
+			counts[resource.Type]++
+			key := region + "/" + resource.Type + "/" + resource.ID
+			seen.Insert(key)
+			since, ok := c.orphanedSince[key]
+			if !ok {
+				since = now
+				c.orphanedSince[key] = now
+			}
+			if now.Sub(since) < c.GracePeriod {
+				continue
+			}
+
+			log := log.WithValues("resource-type", resource.Type, "id", resource.ID, "cluster-namespace", resource.ClusterNamespace, "cluster", resource.ClusterName)
+			if c.DryRun {
+				log.Info("Found orphaned resource, not deleting it in dry-run mode", "orphaned-since", since)
+				continue
+			}
+			if err := gcSvc.DeleteResource(resource); err != nil {
+				orphanedResourcesDeleteErrors.WithLabelValues(region, resource.Type).Inc()
+				log.Error(err, "Failed to delete orphaned resource")
+				continue
+			}
+			orphanedResourcesDeleted.WithLabelValues(region, resource.Type).Inc()
+			log.Info("Deleted orphaned resource", "orphaned-since", since)
+			delete(c.orphanedSince, key)
+		}
+		for resourceType, count := range counts {
+			orphanedResources.WithLabelValues(region, resourceType).Set(count)
+		}
+	}
+
+	// forget the resources which have been deleted or are referenced again
+	for key := range c.orphanedSince {
+		if !seen.Has(key) {
+			delete(c.orphanedSince, key)
+		}
+	}
+	return nil
+}
+
+// isOrphaned returns true if the owned resource belongs to a cluster which no longer exists,
+// or is an instance, a released elastic IP address or a detached disk no ACKMachine refers to.
+// Attached disks and elastic IP addresses are released with their instance, disks which are
+// not deleted with their instance are kept on purpose and are never orphaned.
+func isOrphaned(resource gc.Resource, liveClusters, referenced sets.String) bool {
+	if resource.Attached || resource.Retained {
+		return false
+	}
+	if !liveClusters.Has(resource.ClusterNamespace + "/" + resource.ClusterName) {
+		return true
+	}
+	switch resource.Type {
+	case ecs.ResourceTypeInstance, vpc.ResourceTypeEIP, ecs.ResourceTypeDisk:
+		return !referenced.Has(resource.ID)
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/gc"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/vpc"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestIsOrphaned(t *testing.T) {
+	liveClusters := sets.NewString("default/live")
+	referenced := sets.NewString("i-referenced", "eip-referenced", "d-referenced")
+
+	tests := []struct {
+		name     string
+		resource gc.Resource
+		orphaned bool
+	}{
+		{
+			name:     "instance of a deleted cluster",
+			resource: gc.Resource{Type: ecs.ResourceTypeInstance, ID: "i-referenced", ClusterNamespace: "default", ClusterName: "deleted"},
+			orphaned: true,
+		},
+		{
+			name:     "instance of a cluster of the same name in another namespace",
+			resource: gc.Resource{Type: ecs.ResourceTypeInstance, ID: "i-referenced", ClusterNamespace: "other", ClusterName: "live"},
+			orphaned: true,
+		},
+		{
+			name:     "referenced instance of a live cluster",
+			resource: gc.Resource{Type: ecs.ResourceTypeInstance, ID: "i-referenced", ClusterNamespace: "default", ClusterName: "live"},
+		},
+		{
+			name:     "unreferenced instance of a live cluster",
+			resource: gc.Resource{Type: ecs.ResourceTypeInstance, ID: "i-unreferenced", ClusterNamespace: "default", ClusterName: "live"},
+			orphaned: true,
+		},
+		{
+			name:     "released elastic IP address no ACKMachine refers to",
+			resource: gc.Resource{Type: vpc.ResourceTypeEIP, ID: "eip-unreferenced", ClusterNamespace: "default", ClusterName: "live"},
+			orphaned: true,
+		},
+		{
+			name:     "attached elastic IP address of a deleted cluster",
+			resource: gc.Resource{Type: vpc.ResourceTypeEIP, ID: "eip-unreferenced", ClusterNamespace: "default", ClusterName: "deleted", Attached: true},
+		},
+		{
+			name:     "detached disk of an ACKMachine of a live cluster",
+			resource: gc.Resource{Type: ecs.ResourceTypeDisk, ID: "d-referenced", ClusterNamespace: "default", ClusterName: "live"},
+		},
+		{
+			name:     "detached disk no ACKMachine refers to",
+			resource: gc.Resource{Type: ecs.ResourceTypeDisk, ID: "d-unreferenced", ClusterNamespace: "default", ClusterName: "live"},
+			orphaned: true,
+		},
+		{
+			name:     "detached disk of a deleted cluster",
+			resource: gc.Resource{Type: ecs.ResourceTypeDisk, ID: "d-referenced", ClusterNamespace: "default", ClusterName: "deleted"},
+			orphaned: true,
+		},
+		{
+			name:     "retained disk no ACKMachine refers to",
+			resource: gc.Resource{Type: ecs.ResourceTypeDisk, ID: "d-unreferenced", ClusterNamespace: "default", ClusterName: "live", Retained: true},
+		},
+		{
+			name:     "retained disk of a deleted cluster",
+			resource: gc.Resource{Type: ecs.ResourceTypeDisk, ID: "d-unreferenced", ClusterNamespace: "default", ClusterName: "deleted", Retained: true},
+		},
+		{
+			name:     "security group of a live cluster",
+			resource: gc.Resource{Type: ecs.ResourceTypeSecurityGroup, ID: "sg-1", ClusterNamespace: "default", ClusterName: "live"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isOrphaned(tt.resource, liveClusters, referenced)).To(Equal(tt.orphaned))
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ackv1alpha3 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha3"
+	ackv1alpha4 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -65,7 +66,7 @@ var _ = BeforeSuite(func(done Done) {
 	err = ackv1alpha3.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
-	err = ackv1alpha3.AddToScheme(scheme.Scheme)
+	err = ackv1alpha4.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:scheme
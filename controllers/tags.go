@@ -0,0 +1,63 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// removedTags returns the keys of the additional tags applied last time which are not part
+// of the additional tags anymore.
+func removedTags(obj metav1.Object, additional infrav1.Tags) ([]string, error) {
+	annotation, ok := obj.GetAnnotations()[infrav1.LastAppliedTagsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	lastApplied := infrav1.Tags{}
+	if err := json.Unmarshal([]byte(annotation), &lastApplied); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode annotation %q", infrav1.LastAppliedTagsAnnotation)
+	}
+
+	var removed []string
+	for _, key := range lastApplied.Keys() {
+		if _, ok := additional[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	return removed, nil
+}
+
+// setLastAppliedTags records the applied additional tags.
+func setLastAppliedTags(obj metav1.Object, additional infrav1.Tags) error {
+	data, err := json.Marshal(additional)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode annotation %q", infrav1.LastAppliedTagsAnnotation)
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[infrav1.LastAppliedTagsAnnotation] = string(data)
+	obj.SetAnnotations(annotations)
+	return nil
+}
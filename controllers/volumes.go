@@ -0,0 +1,73 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pendingFilesystemResizes returns the sizes in GiB of the disks resized online whose filesystems
+// have not been grown on the node yet, by disk ID.
+func pendingFilesystemResizes(obj metav1.Object) (map[string]int64, error) {
+	pending := map[string]int64{}
+	annotation, ok := obj.GetAnnotations()[infrav1.FilesystemResizePendingAnnotation]
+	if !ok {
+		return pending, nil
+	}
+
+	if err := json.Unmarshal([]byte(annotation), &pending); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode annotation %q", infrav1.FilesystemResizePendingAnnotation)
+	}
+	return pending, nil
+}
+
+// setPendingFilesystemResizes records the disks resized online.
+func setPendingFilesystemResizes(obj metav1.Object, pending map[string]int64) error {
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode annotation %q", infrav1.FilesystemResizePendingAnnotation)
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[infrav1.FilesystemResizePendingAnnotation] = string(data)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// describeResizes lists the resized disks in a stable order.
+func describeResizes(pending map[string]int64) []string {
+	ids := make([]string, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	resizes := make([]string, 0, len(ids))
+	for _, id := range ids {
+		resizes = append(resizes, fmt.Sprintf("%s to %d GiB", id, pending[id]))
+	}
+	return resizes
+}
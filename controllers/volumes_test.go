@@ -0,0 +1,76 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/util/conditions"
+	"k8s.io/client-go/tools/record"
+)
+
+// fakeDiskService resizes the disks of an instance.
+type fakeDiskService struct {
+	services.ECSMachineInterface
+	resized map[string]int64
+}
+
+func (s *fakeDiskService) ResizeDisk(id string, size int64) error {
+	s.resized[id] = size
+	return nil
+}
+
+func TestReconcileVolumeSizesKeepsFilesystemResizePending(t *testing.T) {
+	g := NewWithT(t)
+
+	ackMachine := &infrav1.ACKMachine{
+		Spec: infrav1.ACKMachineSpec{
+			MachineVolumeSpec: infrav1.MachineVolumeSpec{
+				SystemDisk: infrav1.SystemDisk{Size: "40"},
+				DataDisks:  []*infrav1.DataDisk{{Size: "200"}},
+			},
+		},
+		Status: infrav1.ACKMachineStatus{
+			Volumes: []infrav1.Volume{
+				{ID: "d-system", Type: infrav1.VolumeTypeSystem, Category: string(infrav1.DiskCategoryCloudEfficiency), Size: 40},
+				{ID: "d-data", Device: "/dev/xvdb", Type: infrav1.VolumeTypeData, Category: string(infrav1.DiskCategoryCloudEfficiency), Size: 100},
+			},
+		},
+	}
+	machineScope := &scope.MachineScope{ACKMachine: ackMachine}
+	ecsSvc := &fakeDiskService{resized: map[string]int64{}}
+	r := &ACKMachineReconciler{Recorder: record.NewFakeRecorder(10)}
+
+	g.Expect(r.reconcileVolumeSizes(machineScope, ecsSvc)).To(Succeed())
+	g.Expect(ecsSvc.resized).To(Equal(map[string]int64{"d-data": 200}))
+	g.Expect(conditions.GetReason(ackMachine, infrav1.VolumesResizedCondition)).To(Equal(infrav1.FilesystemResizePendingReason))
+	g.Expect(ackMachine.Annotations).To(HaveKey(infrav1.FilesystemResizePendingAnnotation))
+
+	// the disk reports its new size on the next reconcile, the filesystem has not been grown yet
+	g.Expect(r.reconcileVolumeSizes(machineScope, ecsSvc)).To(Succeed())
+	g.Expect(conditions.GetReason(ackMachine, infrav1.VolumesResizedCondition)).To(Equal(infrav1.FilesystemResizePendingReason))
+
+	// the annotation is removed once the filesystem has been grown on the node
+	delete(ackMachine.Annotations, infrav1.FilesystemResizePendingAnnotation)
+	g.Expect(r.reconcileVolumeSizes(machineScope, ecsSvc)).To(Succeed())
+	g.Expect(conditions.IsTrue(ackMachine, infrav1.VolumesResizedCondition)).To(BeTrue())
+}
@@ -7,8 +7,14 @@ require (
 	github.com/go-logr/logr v0.1.0
 	github.com/onsi/ginkgo v1.12.0
 	github.com/onsi/gomega v1.9.0
+	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.5.0
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0
+	k8s.io/api v0.17.2
 	k8s.io/apimachinery v0.17.2
 	k8s.io/client-go v0.17.2
+	k8s.io/klog v1.0.0
+	k8s.io/utils v0.0.0-20200229041039-0a110f9eb7ab
 	sigs.k8s.io/cluster-api v0.3.4
 	sigs.k8s.io/controller-runtime v0.5.2
 )
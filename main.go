@@ -19,6 +19,8 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -29,7 +31,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ackv1alpha3 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha3"
+	ackv1alpha4 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
 	"github.com/IrisIris/cluster-api-provider-aliyun/controllers"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -42,6 +47,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(ackv1alpha3.AddToScheme(scheme))
+	utilruntime.Must(ackv1alpha4.AddToScheme(scheme))
 
 	utilruntime.Must(clusterv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
@@ -50,14 +56,57 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var enableOrphanCollector bool
+	var orphanCollectorRegions string
+	var orphanCollectorInterval time.Duration
+	var orphanCollectorGracePeriod time.Duration
+	var orphanCollectorDryRun bool
+	var apiRateLimits string
+	var apiMaxRetries int
+	var apiRetryBaseDelay time.Duration
+	var apiRetryMaxDelay time.Duration
+	var instanceCacheSyncInterval time.Duration
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&enableOrphanCollector, "enable-orphan-collector", false,
+		"Enable the collector deleting the resources left behind by deleted clusters and failed machines.")
+	flag.StringVar(&orphanCollectorRegions, "orphan-collector-regions", "",
+		"Comma separated regions the orphan collector scans in addition to the regions of the existing ACKClusters.")
+	flag.DurationVar(&orphanCollectorInterval, "orphan-collector-interval", 10*time.Minute,
+		"The interval of the orphan collector.")
+	flag.DurationVar(&orphanCollectorGracePeriod, "orphan-collector-grace-period", time.Hour,
+		"How long a resource has to be orphaned before the orphan collector deletes it.")
+	flag.BoolVar(&orphanCollectorDryRun, "orphan-collector-dry-run", false,
+		"Only report the orphaned resources instead of deleting them.")
+	flag.StringVar(&apiRateLimits, "api-rate-limits", "",
+		"Comma separated client-side rate limits of the aliyun apis per region, in the form <group>=<qps>:<burst>, "+
+			"e.g. ecs=10:20,vpc=10:20. The groups are ecs, vpc, slb and resourcemanager, unset groups keep their defaults.")
+	flag.IntVar(&apiMaxRetries, "api-max-retries", throttle.DefaultOptions().MaxRetries,
+		"How often an aliyun api call is retried after being throttled.")
+	flag.DurationVar(&apiRetryBaseDelay, "api-retry-base-delay", throttle.DefaultOptions().BaseDelay,
+		"The maximum delay before the first retry of a throttled aliyun api call, it doubles with every retry.")
+	flag.DurationVar(&apiRetryMaxDelay, "api-retry-max-delay", throttle.DefaultOptions().MaxDelay,
+		"The maximum delay before retrying a throttled aliyun api call.")
+	flag.DurationVar(&instanceCacheSyncInterval, "instance-cache-sync-interval", 10*time.Second,
+		"The interval the shared cache of the ECS instances of the ACKMachines is synced in, 0 disables the cache.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
 
+	limits, err := throttle.ParseLimits(apiRateLimits)
+	if err != nil {
+		setupLog.Error(err, "invalid api rate limits")
+		os.Exit(1)
+	}
+	throttle.Configure(throttle.Options{
+		Limits:     limits,
+		MaxRetries: apiMaxRetries,
+		BaseDelay:  apiRetryBaseDelay,
+		MaxDelay:   apiRetryMaxDelay,
+	})
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
@@ -70,22 +119,71 @@ func main() {
 		os.Exit(1)
 	}
 
+	var instanceCache *ecs.InstanceCache
+	if instanceCacheSyncInterval > 0 {
+		instanceCache = ecs.NewInstanceCache(ctrl.Log.WithName("ecs").WithName("InstanceCache"), instanceCacheSyncInterval)
+	}
 	if err = (&controllers.ACKMachineReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("ACKMachine"),
-		Scheme: mgr.GetScheme(),
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName("ACKMachine"),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("ackmachine-controller"),
+		InstanceCache: instanceCache,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ACKMachine")
 		os.Exit(1)
 	}
 	if err = (&controllers.ACKClusterReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("ACKCluster"),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("ACKCluster"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ackcluster-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ACKCluster")
 		os.Exit(1)
 	}
+	if err = (&controllers.ACKImageReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("ACKImage"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("ackimage-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ACKImage")
+		os.Exit(1)
+	}
+	if enableOrphanCollector {
+		var regions []string
+		if orphanCollectorRegions != "" {
+			regions = strings.Split(orphanCollectorRegions, ",")
+		}
+		if err = (&controllers.OrphanCollector{
+			Client:      mgr.GetClient(),
+			Log:         ctrl.Log.WithName("controllers").WithName("OrphanCollector"),
+			Regions:     regions,
+			Interval:    orphanCollectorInterval,
+			GracePeriod: orphanCollectorGracePeriod,
+			DryRun:      orphanCollectorDryRun,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create orphan collector")
+			os.Exit(1)
+		}
+	}
+	if err = (&ackv1alpha3.ACKMachine{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ACKMachine")
+		os.Exit(1)
+	}
+	if err = (&ackv1alpha4.ACKMachine{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ACKMachine")
+		os.Exit(1)
+	}
+	if err = (&ackv1alpha3.ACKCluster{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ACKCluster")
+		os.Exit(1)
+	}
+	if err = (&ackv1alpha4.ACKCluster{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ACKCluster")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -0,0 +1,238 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package aliyunerrors classifies the errors returned by the aliyun api, so the controllers
+// can tell the errors which go away by retrying from the ones which need the spec or the
+// account to be fixed.
+package aliyunerrors
+
+import (
+	"net"
+	"strings"
+
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/pkg/errors"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+)
+
+// Kind is the class of an aliyun api error.
+type Kind string
+
+const (
+	// Throttling is returned when the request rate of the api has been exceeded.
+	Throttling = Kind("Throttling")
+	// ServiceUnavailable is returned when the api is unavailable or failed internally.
+	ServiceUnavailable = Kind("ServiceUnavailable")
+	// Timeout is returned when the api could not be reached in time.
+	Timeout = Kind("Timeout")
+	// NoStock is returned when a zone has no stock of the requested resource.
+	NoStock = Kind("NoStock")
+	// QuotaExceed is returned when a quota of the account has been exceeded.
+	QuotaExceed = Kind("QuotaExceed")
+	// InvalidParameter is returned for invalid or missing request parameters.
+	InvalidParameter = Kind("InvalidParameter")
+	// Forbidden is returned when the RAM user is not allowed to call the api.
+	Forbidden = Kind("Forbidden")
+	// IncorrectInstanceStatus is returned when the instance is not in a state the operation is allowed in.
+	IncorrectInstanceStatus = Kind("IncorrectInstanceStatus")
+	// DependencyViolation is returned when a resource is still in use by another one.
+	DependencyViolation = Kind("DependencyViolation")
+	// NotFound is returned when the resource doesn't exist.
+	NotFound = Kind("NotFound")
+	// Unknown is any other error of the aliyun api.
+	Unknown = Kind("Unknown")
+)
+
+// Error is a classified error of the aliyun api.
+type Error struct {
+	Kind Kind
+	// Code is the error code of the api, e.g. Throttling.User or SDK.TimeoutError.
+	Code      string
+	Message   string
+	RequestID string
+
+	err error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Cause returns the sdk error.
+func (e *Error) Cause() error {
+	return e.err
+}
+
+// Classify returns the classified aliyun api error err is caused by, or nil if it is caused
+// by another error.
+func Classify(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	switch cause := errors.Cause(err).(type) {
+	case *sdkerrors.ServerError:
+		return &Error{
+			Kind:      classifyCode(cause.ErrorCode()),
+			Code:      cause.ErrorCode(),
+			Message:   cause.Message(),
+			RequestID: cause.RequestId(),
+			err:       cause,
+		}
+	case *sdkerrors.ClientError:
+		kind := Unknown
+		if cause.ErrorCode() == sdkerrors.TimeoutErrorCode {
+			kind = Timeout
+		}
+		return &Error{Kind: kind, Code: cause.ErrorCode(), Message: cause.Message(), err: cause}
+	case net.Error:
+		return &Error{Kind: Timeout, Message: cause.Error(), err: cause}
+	}
+	return nil
+}
+
+// classifyCode maps an error code of the aliyun api to its kind. The codes are matched by
+// their well-known parts, the apis qualify them differently, e.g. Throttling.User,
+// OperationDenied.NoStock or QuotaExceed.ElasticQuota.
+func classifyCode(code string) Kind {
+	switch {
+	case strings.HasPrefix(code, "Throttling"):
+		return Throttling
+	case strings.HasPrefix(code, "ServiceUnavailable"), strings.HasPrefix(code, "InternalError"), strings.HasPrefix(code, "UnknownError"):
+		return ServiceUnavailable
+	case strings.Contains(code, "NoStock"):
+		return NoStock
+	case strings.Contains(code, "QuotaExceed"):
+		return QuotaExceed
+	case strings.HasPrefix(code, "Forbidden"):
+		return Forbidden
+	case strings.HasPrefix(code, "IncorrectInstanceStatus"), strings.HasPrefix(code, "IncorrectStatus"), strings.HasPrefix(code, "InvalidStatus"):
+		return IncorrectInstanceStatus
+	case strings.HasPrefix(code, "DependencyViolation"):
+		return DependencyViolation
+	case strings.HasSuffix(code, "NotFound"), strings.Contains(code, "NotExist"):
+		return NotFound
+	case strings.HasPrefix(code, "Invalid"), strings.HasPrefix(code, "MissingParameter"), strings.HasSuffix(code, ".Malformed"):
+		// the codes of an invalid parameter are named after it, e.g. InvalidVSwitchId.NotBelongToZone,
+		// except the ones of a missing resource matched above
+		return InvalidParameter
+	}
+	return Unknown
+}
+
+// Code returns the error code of the aliyun api err is caused by.
+func Code(err error) (string, bool) {
+	if e := Classify(err); e != nil && e.Code != "" {
+		return e.Code, true
+	}
+	return "", false
+}
+
+// IsKind returns true if err is caused by an aliyun api error of one of the kinds.
+func IsKind(err error, kinds ...Kind) bool {
+	e := Classify(err)
+	if e == nil {
+		return false
+	}
+	for _, kind := range kinds {
+		if e.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRetryable returns true if retrying the request later can succeed, because the api is
+// throttled or unavailable, the stock of the zone is exhausted, or the resource is in a
+// transitional state.
+func IsRetryable(err error) bool {
+	return IsKind(err, Throttling, ServiceUnavailable, Timeout, NoStock, IncorrectInstanceStatus, DependencyViolation)
+}
+
+// IsTerminal returns true if retrying the request fails the same way until the spec or the
+// account has been fixed.
+func IsTerminal(err error) bool {
+	return IsKind(err, InvalidParameter, QuotaExceed, Forbidden)
+}
+
+// IsNotFound returns true if the resource doesn't exist.
+func IsNotFound(err error) bool {
+	return IsKind(err, NotFound)
+}
+
+// IsQuota returns true if a quota of the account has been exceeded.
+func IsQuota(err error) bool {
+	return IsKind(err, QuotaExceed)
+}
+
+// IsThrottling returns true if the request rate of the api has been exceeded.
+func IsThrottling(err error) bool {
+	return IsKind(err, Throttling)
+}
+
+// IsNoStock returns true if the zone has no stock of the requested resource.
+func IsNoStock(err error) bool {
+	return IsKind(err, NoStock)
+}
+
+// IsInvalidParameter returns true if the request parameters are invalid or missing.
+func IsInvalidParameter(err error) bool {
+	return IsKind(err, InvalidParameter)
+}
+
+// IsForbidden returns true if the RAM user is not allowed to call the api.
+func IsForbidden(err error) bool {
+	return IsKind(err, Forbidden)
+}
+
+// IsIncorrectInstanceStatus returns true if the instance is not in a state the operation is allowed in.
+func IsIncorrectInstanceStatus(err error) bool {
+	return IsKind(err, IncorrectInstanceStatus)
+}
+
+// IsDependencyViolation returns true if the resource can't be deleted because another
+// resource still depends on it.
+func IsDependencyViolation(err error) bool {
+	return IsKind(err, DependencyViolation)
+}
+
+// MachineStatusError returns the failure reason of a machine for a terminal error, invalid
+// parameters are invalid configurations and exceeded quotas are insufficient resources, any
+// other terminal error fails the operation. It returns false if the error is not terminal.
+func MachineStatusError(err error, operation capierrors.MachineStatusError) (capierrors.MachineStatusError, bool) {
+	switch {
+	case IsInvalidParameter(err):
+		return capierrors.InvalidConfigurationMachineError, true
+	case IsQuota(err):
+		return capierrors.InsufficientResourcesMachineError, true
+	case IsTerminal(err):
+		return operation, true
+	}
+	return "", false
+}
+
+// ClusterStatusError returns the failure reason of a cluster for a terminal error, invalid
+// parameters are invalid configurations, any other terminal error fails the operation. It
+// returns false if the error is not terminal.
+func ClusterStatusError(err error, operation capierrors.ClusterStatusError) (capierrors.ClusterStatusError, bool) {
+	switch {
+	case IsInvalidParameter(err):
+		return capierrors.InvalidConfigurationClusterError, true
+	case IsTerminal(err):
+		return operation, true
+	}
+	return "", false
+}
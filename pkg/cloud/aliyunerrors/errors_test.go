@@ -0,0 +1,164 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aliyunerrors
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/pkg/errors"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+)
+
+func serverError(code string) error {
+	return sdkerrors.NewServerError(400, fmt.Sprintf(`{"Code":%q,"Message":"message","RequestId":"request-id"}`, code), "")
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind Kind
+		code string
+	}{
+		{name: "throttling", err: serverError("Throttling"), kind: Throttling, code: "Throttling"},
+		{name: "qualified throttling", err: serverError("Throttling.User"), kind: Throttling, code: "Throttling.User"},
+		{name: "service unavailable", err: serverError("ServiceUnavailable"), kind: ServiceUnavailable, code: "ServiceUnavailable"},
+		{name: "internal error", err: serverError("InternalError"), kind: ServiceUnavailable, code: "InternalError"},
+		{name: "no stock", err: serverError("OperationDenied.NoStock"), kind: NoStock, code: "OperationDenied.NoStock"},
+		{name: "quota exceeded", err: serverError("QuotaExceed.ElasticQuota"), kind: QuotaExceed, code: "QuotaExceed.ElasticQuota"},
+		{name: "invalid parameter", err: serverError("InvalidParameter"), kind: InvalidParameter, code: "InvalidParameter"},
+		{name: "missing parameter", err: serverError("MissingParameter"), kind: InvalidParameter, code: "MissingParameter"},
+		{name: "malformed parameter", err: serverError("InvalidInstanceType.Malformed"), kind: InvalidParameter, code: "InvalidInstanceType.Malformed"},
+		{name: "unsupported instance type", err: serverError("InvalidInstanceType.ValueNotSupported"), kind: InvalidParameter, code: "InvalidInstanceType.ValueNotSupported"},
+		{name: "unsupported system disk category", err: serverError("InvalidSystemDiskCategory.ValueNotSupported"), kind: InvalidParameter, code: "InvalidSystemDiskCategory.ValueNotSupported"},
+		{name: "vswitch of another zone", err: serverError("InvalidVSwitchId.NotBelongToZone"), kind: InvalidParameter, code: "InvalidVSwitchId.NotBelongToZone"},
+		{name: "forbidden by ram", err: serverError("Forbidden.RAM"), kind: Forbidden, code: "Forbidden.RAM"},
+		{name: "incorrect instance status", err: serverError("IncorrectInstanceStatus"), kind: IncorrectInstanceStatus, code: "IncorrectInstanceStatus"},
+		{name: "qualified incorrect instance status", err: serverError("IncorrectInstanceStatus.Initializing"), kind: IncorrectInstanceStatus, code: "IncorrectInstanceStatus.Initializing"},
+		{name: "invalid resource status", err: serverError("InvalidStatus.ResourceStatus"), kind: IncorrectInstanceStatus, code: "InvalidStatus.ResourceStatus"},
+		{name: "dependency violation", err: serverError("DependencyViolation.Instance"), kind: DependencyViolation, code: "DependencyViolation.Instance"},
+		{name: "instance not found", err: serverError("InvalidInstanceId.NotFound"), kind: NotFound, code: "InvalidInstanceId.NotFound"},
+		{name: "entity not exist", err: serverError("EntityNotExist.ResourceGroup"), kind: NotFound, code: "EntityNotExist.ResourceGroup"},
+		{name: "unknown server error", err: serverError("Account.Arrearage"), kind: Unknown, code: "Account.Arrearage"},
+		{name: "client timeout", err: sdkerrors.NewClientError(sdkerrors.TimeoutErrorCode, "timeout", nil), kind: Timeout, code: sdkerrors.TimeoutErrorCode},
+		{name: "client error", err: sdkerrors.NewClientError(sdkerrors.InvalidParamErrorCode, "invalid", nil), kind: Unknown, code: sdkerrors.InvalidParamErrorCode},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, kind: Timeout},
+		{name: "wrapped error", err: errors.Wrap(serverError("Throttling"), "failed to describe instance"), kind: Throttling, code: "Throttling"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			classified := Classify(tt.err)
+			g.Expect(classified).NotTo(BeNil())
+			g.Expect(classified.Kind).To(Equal(tt.kind))
+			g.Expect(classified.Code).To(Equal(tt.code))
+		})
+	}
+}
+
+func TestClassifyOtherErrors(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(Classify(nil)).To(BeNil())
+	g.Expect(Classify(errors.New("not an aliyun error"))).To(BeNil())
+	g.Expect(IsRetryable(errors.New("not an aliyun error"))).To(BeFalse())
+	g.Expect(IsTerminal(errors.New("not an aliyun error"))).To(BeFalse())
+}
+
+func TestPredicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+		terminal  bool
+		notFound  bool
+		quota     bool
+	}{
+		{name: "throttling", err: serverError("Throttling.User"), retryable: true},
+		{name: "service unavailable", err: serverError("ServiceUnavailable"), retryable: true},
+		{name: "no stock", err: serverError("OperationDenied.NoStock"), retryable: true},
+		{name: "incorrect instance status", err: serverError("IncorrectInstanceStatus"), retryable: true},
+		{name: "dependency violation", err: serverError("DependencyViolation"), retryable: true},
+		{name: "client timeout", err: sdkerrors.NewClientError(sdkerrors.TimeoutErrorCode, "timeout", nil), retryable: true},
+		{name: "quota exceeded", err: serverError("QuotaExceed"), terminal: true, quota: true},
+		{name: "invalid parameter", err: serverError("InvalidParameter"), terminal: true},
+		{name: "forbidden by ram", err: serverError("Forbidden.RAM"), terminal: true},
+		{name: "not found", err: serverError("InvalidVSwitchId.NotFound"), notFound: true},
+		{name: "unknown", err: serverError("Account.Arrearage")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsRetryable(tt.err)).To(Equal(tt.retryable))
+			g.Expect(IsTerminal(tt.err)).To(Equal(tt.terminal))
+			g.Expect(IsNotFound(tt.err)).To(Equal(tt.notFound))
+			g.Expect(IsQuota(tt.err)).To(Equal(tt.quota))
+		})
+	}
+}
+
+func TestMachineStatusError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		operation capierrors.MachineStatusError
+		reason    capierrors.MachineStatusError
+		terminal  bool
+	}{
+		{name: "invalid parameter", err: serverError("InvalidParameter"), operation: capierrors.CreateMachineError, reason: capierrors.InvalidConfigurationMachineError, terminal: true},
+		{name: "quota exceeded", err: serverError("QuotaExceed.ElasticQuota"), operation: capierrors.CreateMachineError, reason: capierrors.InsufficientResourcesMachineError, terminal: true},
+		{name: "forbidden on create", err: serverError("Forbidden.RAM"), operation: capierrors.CreateMachineError, reason: capierrors.CreateMachineError, terminal: true},
+		{name: "forbidden on delete", err: serverError("Forbidden.RAM"), operation: capierrors.DeleteMachineError, reason: capierrors.DeleteMachineError, terminal: true},
+		{name: "throttling", err: serverError("Throttling"), operation: capierrors.UpdateMachineError},
+		{name: "no stock", err: serverError("OperationDenied.NoStock"), operation: capierrors.CreateMachineError},
+		{name: "other error", err: errors.New("not an aliyun error"), operation: capierrors.UpdateMachineError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			reason, terminal := MachineStatusError(tt.err, tt.operation)
+			g.Expect(terminal).To(Equal(tt.terminal))
+			g.Expect(reason).To(Equal(tt.reason))
+		})
+	}
+}
+
+func TestClusterStatusError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		operation capierrors.ClusterStatusError
+		reason    capierrors.ClusterStatusError
+		terminal  bool
+	}{
+		{name: "invalid parameter", err: serverError("InvalidParameter"), operation: capierrors.CreateClusterError, reason: capierrors.InvalidConfigurationClusterError, terminal: true},
+		{name: "quota exceeded", err: serverError("QuotaExceed"), operation: capierrors.UpdateClusterError, reason: capierrors.UpdateClusterError, terminal: true},
+		{name: "throttling", err: serverError("Throttling"), operation: capierrors.CreateClusterError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			reason, terminal := ClusterStatusError(tt.err, tt.operation)
+			g.Expect(terminal).To(Equal(tt.terminal))
+			g.Expect(reason).To(Equal(tt.reason))
+		})
+	}
+}
@@ -1,9 +1,62 @@
 package scope
 
 import (
-	svcs "github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services"
+	"os"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/resourcemanager"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/slb"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// AccessKeyIDEnv is the environment variable holding the aliyun AccessKey ID.
+	AccessKeyIDEnv = "ALIBABA_CLOUD_ACCESS_KEY_ID"
+	// AccessKeySecretEnv is the environment variable holding the aliyun AccessKey Secret.
+	AccessKeySecretEnv = "ALIBABA_CLOUD_ACCESS_KEY_SECRET"
 )
 
+// ACKClients contains all the aliyun clients used by the scopes.
 type ACKClients struct {
-	ECS svcs.ECSMachineInterface
+	ECS             *ecs.Client
+	VPC             *vpc.Client
+	ResourceManager *resourcemanager.Client
+	SLB             *slb.Client
+}
+
+// newECSClient creates an ecs client for the region with the credentials of the manager.
+func newECSClient(regionId string) (*ecs.Client, error) {
+	client, err := ecs.NewClientWithAccessKey(regionId, os.Getenv(AccessKeyIDEnv), os.Getenv(AccessKeySecretEnv))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create ecs client for region %q", regionId)
+	}
+	return client, nil
+}
+
+// newVPCClient creates a vpc client for the region with the credentials of the manager.
+func newVPCClient(regionId string) (*vpc.Client, error) {
+	client, err := vpc.NewClientWithAccessKey(regionId, os.Getenv(AccessKeyIDEnv), os.Getenv(AccessKeySecretEnv))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create vpc client for region %q", regionId)
+	}
+	return client, nil
+}
+
+// newResourceManagerClient creates a resourcemanager client for the region with the credentials of the manager.
+func newResourceManagerClient(regionId string) (*resourcemanager.Client, error) {
+	client, err := resourcemanager.NewClientWithAccessKey(regionId, os.Getenv(AccessKeyIDEnv), os.Getenv(AccessKeySecretEnv))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create resourcemanager client for region %q", regionId)
+	}
+	return client, nil
+}
+
+// newSLBClient creates a slb client for the region with the credentials of the manager.
+func newSLBClient(regionId string) (*slb.Client, error) {
+	client, err := slb.NewClientWithAccessKey(regionId, os.Getenv(AccessKeyIDEnv), os.Getenv(AccessKeySecretEnv))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create slb client for region %q", regionId)
+	}
+	return client, nil
 }
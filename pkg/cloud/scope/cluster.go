@@ -2,17 +2,15 @@ package scope
 
 import (
 	"context"
-	providerv1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha3"
-	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"k8s.io/klog/klogr"
+	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util/patch"
-	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -26,7 +24,7 @@ type ClusterScopeParams struct {
 	Client     client.Client
 	Logger     logr.Logger
 	Cluster    *clusterv1.Cluster
-	ACKCluster *providerv1.ACKCluster
+	ACKCluster *infrav1.ACKCluster
 }
 
 // ClusterScope defines the basic context for an actuator to operate upon.
@@ -37,7 +35,7 @@ type ClusterScope struct {
 
 	ACKClients
 	Cluster    *clusterv1.Cluster
-	ACKCluster *providerv1.ACKCluster
+	ACKCluster *infrav1.ACKCluster
 }
 
 func NewClusterScope(params *ClusterScopeParams) (*ClusterScope, error) {
@@ -52,9 +50,33 @@ func NewClusterScope(params *ClusterScopeParams) (*ClusterScope, error) {
 		params.Logger = klogr.New()
 	}
 
-	// TODO
 	if params.ACKClients.ECS == nil {
-		// new ecs client
+		ecsClient, err := newECSClient(params.ACKCluster.Spec.RegionId)
+		if err != nil {
+			return nil, err
+		}
+		params.ACKClients.ECS = ecsClient
+	}
+	if params.ACKClients.VPC == nil {
+		vpcClient, err := newVPCClient(params.ACKCluster.Spec.RegionId)
+		if err != nil {
+			return nil, err
+		}
+		params.ACKClients.VPC = vpcClient
+	}
+	if params.ACKClients.ResourceManager == nil {
+		resourceManagerClient, err := newResourceManagerClient(params.ACKCluster.Spec.RegionId)
+		if err != nil {
+			return nil, err
+		}
+		params.ACKClients.ResourceManager = resourceManagerClient
+	}
+	if params.ACKClients.SLB == nil {
+		slbClient, err := newSLBClient(params.ACKCluster.Spec.RegionId)
+		if err != nil {
+			return nil, err
+		}
+		params.ACKClients.SLB = slbClient
 	}
 
 	helper, err := patch.NewHelper(params.ACKCluster, params.Client)
@@ -72,43 +94,64 @@ func NewClusterScope(params *ClusterScopeParams) (*ClusterScope, error) {
 	}, nil
 }
 
-// Close closes the current scope persisting the cluster configuration and status.
-func (s *ClusterScope) Close() error {
-	return s.PatchObject()
+// Region returns the ACKCluster region.
+func (s *ClusterScope) Region() string {
+	return s.ACKCluster.Spec.RegionId
 }
 
-// PatchObject persists the cluster configuration and status.
-func (s *ClusterScope) PatchObject() error {
-	return s.patchHelper.Patch(context.TODO(), s.ACKCluster)
+// Name returns the name of the cluster the ACKCluster belongs to.
+func (s *ClusterScope) Name() string {
+	return s.Cluster.Name
 }
 
-func (s *ClusterScope) ReconcileDelete() (ctrl.Result, error) {
-	s.Info("")
-	ecsSvc := ecs.NewService()
+// Namespace returns the namespace of the cluster.
+func (s *ClusterScope) Namespace() string {
+	return s.Cluster.Namespace
+}
 
-	// todo delete network
-	// todo delete load balancer
+// ResourceGroupID returns the resource group the resources of the cluster are created in.
+func (s *ClusterScope) ResourceGroupID() string {
+	return s.ACKCluster.Spec.ResourceGroupId
+}
 
-	// if cluster is deleted remove the finalizer
-	controllerutil.RemoveFinalizer(s.ACKCluster, ClusterFinalizer)
-	return ctrl.Result{}, nil
+// AdditionalTags returns the tags of the ACKCluster applied to all resources of the cluster.
+func (s *ClusterScope) AdditionalTags() infrav1.Tags {
+	tags := infrav1.Tags{}
+	tags.Merge(s.ACKCluster.Spec.Tags)
+	return tags
 }
 
-func (s *ClusterScope) ReconcileNormal() (reconcile.Result, error) {
-	s.Info("Reconciling ACKCluster")
-	ackCluster := s.ACKCluster
+// Tags returns the tags of a resource of the cluster with the given lifecycle.
+func (s *ClusterScope) Tags(lifecycle infrav1.ResourceLifecycle) infrav1.Tags {
+	return infrav1.Build(s.Namespace(), s.Name(), lifecycle, s.AdditionalTags())
+}
 
-	// add finalizer if not exits
-	controllerutil.AddFinalizer(ackCluster, ClusterFinalizer)
-	if err := s.PatchObject(); err != nil {
-		return reconcile.Result{}, nil
-	}
+// SetFailureDomains sets the ACKCluster status failure domains.
+func (s *ClusterScope) SetFailureDomains(domains clusterv1.FailureDomains) {
+	s.ACKCluster.Status.FailureDomains = domains
+}
 
-	//ecsService := ecs.NewService()
-	// todo:ReconcileNetwork
-	//ecsService.ReconcileNetwork()
-	// todo: ReconcileLoadbalancers
+// HasFailed returns true if reconciling the ACKCluster failed terminally.
+func (s *ClusterScope) HasFailed() bool {
+	return s.ACKCluster.Status.FailureReason != nil || s.ACKCluster.Status.FailureMessage != nil
+}
+
+// SetFailureReason sets the ACKCluster status failure reason.
+func (s *ClusterScope) SetFailureReason(v capierrors.ClusterStatusError) {
+	s.ACKCluster.Status.FailureReason = &v
+}
 
-	ackCluster.Status.Ready = true
-	return reconcile.Result{}, nil
+// SetFailureMessage sets the ACKCluster status failure message.
+func (s *ClusterScope) SetFailureMessage(v error) {
+	s.ACKCluster.Status.FailureMessage = pointer.StringPtr(v.Error())
+}
+
+// Close closes the current scope persisting the cluster configuration and status.
+func (s *ClusterScope) Close() error {
+	return s.PatchObject()
+}
+
+// PatchObject persists the cluster configuration and status.
+func (s *ClusterScope) PatchObject() error {
+	return s.patchHelper.Patch(context.TODO(), s.ACKCluster)
 }
@@ -2,13 +2,18 @@ package scope
 
 import (
 	"context"
-	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha3"
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/klogr"
 	"k8s.io/utils/pointer"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -28,7 +33,7 @@ type MachineScope struct {
 
 	Cluster    *clusterv1.Cluster
 	Machine    *clusterv1.Machine
-	ACKCkuster *infrav1.ACKCluster
+	ACKCluster *infrav1.ACKCluster
 	ACKMachine *infrav1.ACKMachine
 }
 
@@ -40,7 +45,7 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 		return nil, errors.Errorf("failed to create machine scope due to empty cluster")
 	}
 	if params.Machine == nil {
-		return nil, errors.Errorf("failed to create machine scope due to empty client")
+		return nil, errors.Errorf("failed to create machine scope due to empty machine")
 	}
 	if params.ACKCluster == nil {
 		return nil, errors.Errorf("failed to create machine scope due to empty ACKCluster")
@@ -64,7 +69,7 @@ func NewMachineScope(params MachineScopeParams) (*MachineScope, error) {
 		patchHelper: helper,
 		Cluster:     params.Cluster,
 		Machine:     params.Machine,
-		ACKCkuster:  params.ACKCluster,
+		ACKCluster:  params.ACKCluster,
 		ACKMachine:  params.ACKMachine,
 	}, nil
 }
@@ -87,3 +92,163 @@ func (m *MachineScope) SetFailureMessage(v error) {
 func (m *MachineScope) SetFailureReason(v capierrors.MachineStatusError) {
 	m.ACKMachine.Status.FailureReason = &v
 }
+
+// Close the MachineScope by updating the machine spec, machine status.
+func (m *MachineScope) Close() error {
+	return m.PatchObject()
+}
+
+// ResourceGroupID returns the resource group the resources of the ACKMachine are created in,
+// the resource group of the ACKMachine overrides the one of the ACKCluster.
+func (m *MachineScope) ResourceGroupID() string {
+	if m.ACKMachine.Spec.ResourceGroupId != "" {
+		return m.ACKMachine.Spec.ResourceGroupId
+	}
+	return m.ACKCluster.Spec.ResourceGroupId
+}
+
+// AdditionalTags merges the tags of the ACKCluster and the ACKMachine, the tags of the ACKMachine win.
+func (m *MachineScope) AdditionalTags() infrav1.Tags {
+	tags := infrav1.Tags{}
+	tags.Merge(m.ACKCluster.Spec.Tags)
+	tags.Merge(m.ACKMachine.Spec.Tags)
+	return tags
+}
+
+// Tags returns the tags of the resources created for the ACKMachine, which are owned by the cluster.
+func (m *MachineScope) Tags() infrav1.Tags {
+	return infrav1.Build(m.Cluster.Namespace, m.Cluster.Name, infrav1.ResourceLifecycleOwned, m.AdditionalTags())
+}
+
+// IsControlPlane returns true if the machine is a control plane machine.
+func (m *MachineScope) IsControlPlane() bool {
+	return util.IsControlPlaneMachine(m.Machine)
+}
+
+// Name returns the ACKMachine name.
+func (m *MachineScope) Name() string {
+	return m.ACKMachine.Name
+}
+
+// Namespace returns the namespace name.
+func (m *MachineScope) Namespace() string {
+	return m.ACKMachine.Namespace
+}
+
+// GetProviderID returns the ACKMachine providerID from the spec.
+func (m *MachineScope) GetProviderID() string {
+	if m.ACKMachine.Spec.ProviderID != nil {
+		return *m.ACKMachine.Spec.ProviderID
+	}
+	return ""
+}
+
+// SetProviderID sets the ACKMachine providerID in spec.
+func (m *MachineScope) SetProviderID(v string) {
+	m.ACKMachine.Spec.ProviderID = pointer.StringPtr(v)
+}
+
+// GetInstanceID returns the ACKMachine instance id by parsing Spec.ProviderID.
+func (m *MachineScope) GetInstanceID() *string {
+	parsed, err := noderefutil.NewProviderID(m.GetProviderID())
+	if err != nil {
+		return nil
+	}
+	return pointer.StringPtr(parsed.ID())
+}
+
+// GetInstanceState returns the ACKMachine instance state from the status.
+func (m *MachineScope) GetInstanceState() *infrav1.InstanceState {
+	return m.ACKMachine.Status.InstanceState
+}
+
+// SetInstanceState sets the ACKMachine status instance state.
+func (m *MachineScope) SetInstanceState(v infrav1.InstanceState) {
+	m.ACKMachine.Status.InstanceState = &v
+}
+
+// SetAddresses sets the ACKMachine address status.
+func (m *MachineScope) SetAddresses(addrs []clusterv1.MachineAddress) {
+	m.ACKMachine.Status.Addresses = addrs
+}
+
+// SetEIP sets the ACKMachine status elastic IP address.
+func (m *MachineScope) SetEIP(v *infrav1.EIPStatus) {
+	m.ACKMachine.Status.EIP = v
+}
+
+// SetNetworkInterfaces sets the ACKMachine status secondary network interfaces.
+func (m *MachineScope) SetNetworkInterfaces(v []infrav1.NetworkInterfaceStatus) {
+	m.ACKMachine.Status.NetworkInterfaces = v
+}
+
+// SetVolumes sets the ACKMachine status volumes attached to the instance.
+func (m *MachineScope) SetVolumes(v []infrav1.Volume) {
+	m.ACKMachine.Status.Volumes = v
+}
+
+// SetLaunchTemplate sets the ACKMachine status launch template the instance is created from.
+func (m *MachineScope) SetLaunchTemplate(v *infrav1.LaunchTemplateStatus) {
+	m.ACKMachine.Status.LaunchTemplate = v
+}
+
+// SetImageID sets the ACKMachine status image ID.
+func (m *MachineScope) SetImageID(v string) {
+	m.ACKMachine.Status.ImageId = v
+}
+
+// SetPlacement sets the ACKMachine status instance type and zone.
+func (m *MachineScope) SetPlacement(instanceType, zoneID string) {
+	m.ACKMachine.Status.InstanceType = instanceType
+	m.ACKMachine.Status.ZoneId = zoneID
+}
+
+// SetZone sets the ACKMachine status zone and vswitch.
+func (m *MachineScope) SetZone(zoneID, vswitchID string) {
+	m.ACKMachine.Status.ZoneId = zoneID
+	m.ACKMachine.Status.VSwitchId = vswitchID
+}
+
+// AddNoStockZone records a zone without stock in the ACKMachine status.
+func (m *MachineScope) AddNoStockZone(zoneID string) {
+	for _, zone := range m.ACKMachine.Status.NoStockZones {
+		if zone == zoneID {
+			return
+		}
+	}
+	m.ACKMachine.Status.NoStockZones = append(m.ACKMachine.Status.NoStockZones, zoneID)
+}
+
+// SetExpiredTime sets the ACKMachine status expiry time of a PrePaid instance.
+func (m *MachineScope) SetExpiredTime(v *metav1.Time) {
+	m.ACKMachine.Status.ExpiredTime = v
+}
+
+// SetReady sets the ACKMachine Ready Status
+func (m *MachineScope) SetReady() {
+	m.ACKMachine.Status.Ready = true
+}
+
+// SetNotReady sets the ACKMachine Ready Status to false
+func (m *MachineScope) SetNotReady() {
+	m.ACKMachine.Status.Ready = false
+}
+
+// GetRawBootstrapData returns the bootstrap data from the secret in the Machine's bootstrap.dataSecretName.
+func (m *MachineScope) GetRawBootstrapData() ([]byte, error) {
+	if m.Machine.Spec.Bootstrap.DataSecretName == nil {
+		return nil, errors.New("error retrieving bootstrap data: linked Machine's bootstrap.dataSecretName is nil")
+	}
+
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: m.Namespace(), Name: *m.Machine.Spec.Bootstrap.DataSecretName}
+	if err := m.client.Get(context.TODO(), key, secret); err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve bootstrap data secret for ACKMachine %s/%s", m.Namespace(), m.Name())
+	}
+
+	value, ok := secret.Data["value"]
+	if !ok {
+		return nil, errors.New("error retrieving bootstrap data: secret value key is missing")
+	}
+	return value, nil
+}
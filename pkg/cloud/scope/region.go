@@ -0,0 +1,72 @@
+package scope
+
+import (
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/klog/klogr"
+)
+
+// ECSScope is the scope the ecs service operates upon.
+type ECSScope interface {
+	logr.Logger
+	// Region returns the region the api calls are made in.
+	Region() string
+	// ECSClient returns the ecs client of the region.
+	ECSClient() *ecs.Client
+}
+
+// ECSClient returns the ecs client.
+func (c ACKClients) ECSClient() *ecs.Client {
+	return c.ECS
+}
+
+type RegionScopeParams struct {
+	ACKClients
+	Logger logr.Logger
+	Region string
+}
+
+// RegionScope is the scope of the aliyun api calls which are not bound to a cluster,
+// e.g. of building and copying images or of collecting orphaned resources.
+type RegionScope struct {
+	logr.Logger
+	ACKClients
+
+	region string
+}
+
+func NewRegionScope(params RegionScopeParams) (*RegionScope, error) {
+	if params.Region == "" {
+		return nil, errors.New("failed to generate new scope from empty region")
+	}
+	if params.Logger == nil {
+		params.Logger = klogr.New()
+	}
+
+	if params.ACKClients.ECS == nil {
+		ecsClient, err := newECSClient(params.Region)
+		if err != nil {
+			return nil, err
+		}
+		params.ACKClients.ECS = ecsClient
+	}
+	if params.ACKClients.VPC == nil {
+		vpcClient, err := newVPCClient(params.Region)
+		if err != nil {
+			return nil, err
+		}
+		params.ACKClients.VPC = vpcClient
+	}
+
+	return &RegionScope{
+		Logger:     params.Logger.WithValues("region", params.Region),
+		ACKClients: params.ACKClients,
+		region:     params.Region,
+	}, nil
+}
+
+// Region returns the region of the scope.
+func (s *RegionScope) Region() string {
+	return s.region
+}
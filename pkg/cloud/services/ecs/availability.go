@@ -0,0 +1,63 @@
+package ecs
+
+import (
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/pkg/errors"
+)
+
+// AvailableZones returns the zones of the region in which the instance type can be created
+// for the ACKMachine, i.e. is available and has stock.
+func (s *Service) AvailableZones(scope *scope.MachineScope, instanceType string) ([]string, error) {
+	spec := scope.ACKMachine.Spec
+
+	request := ecs.CreateDescribeAvailableResourceRequest()
+	request.RegionId = s.scope.Region()
+	request.DestinationResource = availableResourceInstanceType
+	request.ResourceType = availableResourceTypeInstance
+	request.InstanceType = instanceType
+	request.IoOptimized = spec.IoOptimized
+	request.InstanceChargeType = spec.InstanceChargeType
+	if spec.SpotOptions != nil {
+		request.InstanceChargeType = string(infrav1.InstanceChargeTypePostPaid)
+		request.SpotStrategy = string(spec.SpotOptions.SpotStrategy)
+	}
+
+	var response *ecs.DescribeAvailableResourceResponse
+	err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+		response, err = s.scope.ECSClient().DescribeAvailableResource(request)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe available resources of instance type %q", instanceType)
+	}
+
+	var zones []string
+	for _, zone := range response.AvailableZones.AvailableZone {
+		if zone.Status != availableResourceStatusAvailable {
+			continue
+		}
+		if hasStock(zone.AvailableResources.AvailableResource, instanceType) {
+			zones = append(zones, zone.ZoneId)
+		}
+	}
+
+	s.scope.V(2).Info("Described available zones of instance type", "instance-type", instanceType, "zones", zones)
+	return zones, nil
+}
+
+// hasStock returns true if the instance type is among the available resources with stock.
+func hasStock(resources []ecs.AvailableResource, instanceType string) bool {
+	for _, resource := range resources {
+		for _, supported := range resource.SupportedResources.SupportedResource {
+			if supported.Value == instanceType &&
+				supported.Status == availableResourceStatusAvailable &&
+				supported.StatusCategory == availableResourceStatusCategoryWithStock {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package ecs
+
+import (
+	"strconv"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/pkg/errors"
+)
+
+// setSystemDisk sets the system disk settings of the ACKMachine on the RunInstances request.
+func setSystemDisk(request *ecs.RunInstancesRequest, disk *infrav1.SystemDisk) {
+	request.SystemDiskSize = disk.Size
+	request.SystemDiskCategory = disk.Category
+	request.SystemDiskDiskName = disk.DiskName
+	request.SystemDiskDescription = disk.Description
+	request.SystemDiskPerformanceLevel = disk.PerformanceLevel
+	request.SystemDiskAutoSnapshotPolicyId = disk.AutoSnapshotPolicyId
+}
+
+// sdkDataDisks converts the data disks of the ACKMachine to the RunInstances data disks.
+func sdkDataDisks(disks []*infrav1.DataDisk) []ecs.RunInstancesDataDisk {
+	var result []ecs.RunInstancesDataDisk
+	for _, disk := range disks {
+		if disk == nil {
+			continue
+		}
+		dataDisk := ecs.RunInstancesDataDisk{
+			Size:                 disk.Size,
+			SnapshotId:           disk.SnapshotId,
+			Category:             disk.Category,
+			DiskName:             disk.DiskName,
+			Description:          disk.Description,
+			PerformanceLevel:     disk.PerformanceLevel,
+			AutoSnapshotPolicyId: disk.AutoSnapshotPolicyId,
+		}
+		if disk.Encrypted != nil {
+			dataDisk.Encrypted = strconv.FormatBool(*disk.Encrypted)
+			if *disk.Encrypted {
+				dataDisk.KMSKeyId = disk.KMSKeyId
+			}
+		}
+		if disk.DeleteWithInstance != nil {
+			dataDisk.DeleteWithInstance = strconv.FormatBool(*disk.DeleteWithInstance)
+		}
+		result = append(result, dataDisk)
+	}
+	return result
+}
+
+// GetInstanceVolumes returns the system and data disks attached to the instance.
+func (s *Service) GetInstanceVolumes(id string) ([]infrav1.Volume, error) {
+	request := ecs.CreateDescribeDisksRequest()
+	request.RegionId = s.scope.Region()
+	request.InstanceId = id
+	request.PageSize = requests.NewInteger(maxDescribePageSize)
+
+	var response *ecs.DescribeDisksResponse
+	err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+		response, err = s.scope.ECSClient().DescribeDisks(request)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe disks of instance %q", id)
+	}
+
+	volumes := make([]infrav1.Volume, 0, len(response.Disks.Disk))
+	for _, disk := range response.Disks.Disk {
+		volumes = append(volumes, infrav1.Volume{
+			ID:               disk.DiskId,
+			Name:             disk.DiskName,
+			Device:           disk.Device,
+			Type:             infrav1.VolumeType(disk.Type),
+			Category:         disk.Category,
+			Size:             int64(disk.Size),
+			PerformanceLevel: disk.PerformanceLevel,
+		})
+	}
+	return volumes, nil
+}
+
+// ResizeDisk grows the disk online, the partition and filesystem have to be grown on the node.
+func (s *Service) ResizeDisk(id string, size int64) error {
+	s.scope.V(2).Info("Resizing disk online", "disk-id", id, "size", size)
+
+	request := ecs.CreateResizeDiskRequest()
+	request.RegionId = s.scope.Region()
+	request.DiskId = id
+	request.NewSize = requests.NewInteger64(size)
+	request.Type = diskResizeTypeOnline
+
+	if err := throttle.Do(s.scope.Region(), throttle.ECS, func() error {
+		_, err := s.scope.ECSClient().ResizeDisk(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to resize disk %q to %d GiB", id, size)
+	}
+	return nil
+}
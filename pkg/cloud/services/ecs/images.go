@@ -0,0 +1,181 @@
+package ecs
+
+import (
+	"path"
+	"sort"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/pkg/errors"
+)
+
+// LookupImage returns the ID of the newest available image matching the lookup.
+func (s *Service) LookupImage(lookup *infrav1.ImageLookup) (string, error) {
+	s.scope.V(2).Info("Looking up image", "lookup", lookup)
+
+	request := ecs.CreateDescribeImagesRequest()
+	request.RegionId = s.scope.Region()
+	request.Status = ImageStatusAvailable
+	request.ImageFamily = lookup.ImageFamily
+	request.ImageOwnerAlias = lookup.ImageOwnerAlias
+	if lookup.KubernetesVersion != "" {
+		request.Tag = &[]ecs.DescribeImagesTag{{Key: ImageTagKubernetesVersion, Value: lookup.KubernetesVersion}}
+	}
+	request.PageSize = requests.NewInteger(maxDescribePageSize)
+
+	var images []ecs.Image
+	for page := 1; ; page++ {
+		request.PageNumber = requests.NewInteger(page)
+		var response *ecs.DescribeImagesResponse
+		err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+			response, err = s.scope.ECSClient().DescribeImages(request)
+			return err
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to describe images")
+		}
+		for _, image := range response.Images.Image {
+			if matchOSName(lookup.OSNamePattern, &image) {
+				images = append(images, image)
+			}
+		}
+		if len(response.Images.Image) == 0 || page*maxDescribePageSize >= response.TotalCount {
+			break
+		}
+	}
+
+	if len(images) == 0 {
+		return "", errors.Errorf("no image matches the lookup %+v", *lookup)
+	}
+
+	// the creation time is in ISO 8601 format, e.g. 2020-01-02T15:04:05Z
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].CreationTime > images[j].CreationTime
+	})
+	s.scope.V(2).Info("Resolved image", "image-id", images[0].ImageId, "image-name", images[0].ImageName)
+	return images[0].ImageId, nil
+}
+
+// matchOSName returns true if the Chinese or English OS name of the image matches the pattern.
+func matchOSName(pattern string, image *ecs.Image) bool {
+	if pattern == "" {
+		return true
+	}
+	for _, name := range []string{image.OSName, image.OSNameEn} {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ImageSpec describes an image created from an instance or copied to another region.
+type ImageSpec struct {
+	Name            string
+	ResourceGroupId string
+	Family          string
+	Description     string
+	Tags            infrav1.Tags
+}
+
+// CreateImage creates an image from the system and data disks of a stopped instance.
+func (s *Service) CreateImage(instanceID string, spec *ImageSpec) (string, error) {
+	s.scope.V(2).Info("Creating image", "instance-id", instanceID, "image-name", spec.Name)
+
+	request := ecs.CreateCreateImageRequest()
+	request.RegionId = s.scope.Region()
+	request.InstanceId = instanceID
+	request.ImageName = spec.Name
+	request.ImageFamily = spec.Family
+	request.ResourceGroupId = spec.ResourceGroupId
+	request.Description = spec.Description
+	var tags []ecs.CreateImageTag
+	for _, key := range spec.Tags.Keys() {
+		tags = append(tags, ecs.CreateImageTag{Key: key, Value: spec.Tags[key]})
+	}
+	if len(tags) > 0 {
+		request.Tag = &tags
+	}
+
+	var response *ecs.CreateImageResponse
+	err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+		response, err = s.scope.ECSClient().CreateImage(request)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create image from instance %q", instanceID)
+	}
+	return response.ImageId, nil
+}
+
+// ImageStatus returns the status of the image, or nothing if it doesn't exist.
+func (s *Service) ImageStatus(id string) (string, error) {
+	request := ecs.CreateDescribeImagesRequest()
+	request.RegionId = s.scope.Region()
+	request.ImageId = id
+	request.Status = imageStatusAll
+	request.ShowExpired = requests.NewBoolean(true)
+
+	var response *ecs.DescribeImagesResponse
+	err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+		response, err = s.scope.ECSClient().DescribeImages(request)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe image %q", id)
+	}
+	if len(response.Images.Image) == 0 {
+		return "", nil
+	}
+	return response.Images.Image[0].Status, nil
+}
+
+// ShareImage shares the image with other aliyun accounts.
+func (s *Service) ShareImage(id string, accounts []string) error {
+	s.scope.V(2).Info("Sharing image", "image-id", id, "accounts", accounts)
+
+	request := ecs.CreateModifyImageSharePermissionRequest()
+	request.RegionId = s.scope.Region()
+	request.ImageId = id
+	request.AddAccount = &accounts
+
+	if err := throttle.Do(s.scope.Region(), throttle.ECS, func() error {
+		_, err := s.scope.ECSClient().ModifyImageSharePermission(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to share image %q", id)
+	}
+	return nil
+}
+
+// CopyImage copies the image to the destination region and returns the ID of the copy,
+// which is available once the copy has completed.
+func (s *Service) CopyImage(id, destinationRegion string, spec *ImageSpec) (string, error) {
+	s.scope.V(2).Info("Copying image", "image-id", id, "destination-region", destinationRegion)
+
+	request := ecs.CreateCopyImageRequest()
+	request.RegionId = s.scope.Region()
+	request.ImageId = id
+	request.DestinationRegionId = destinationRegion
+	request.DestinationImageName = spec.Name
+	request.DestinationDescription = spec.Description
+	var tags []ecs.CopyImageTag
+	for _, key := range spec.Tags.Keys() {
+		tags = append(tags, ecs.CopyImageTag{Key: key, Value: spec.Tags[key]})
+	}
+	if len(tags) > 0 {
+		request.Tag = &tags
+	}
+
+	var response *ecs.CopyImageResponse
+	err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+		response, err = s.scope.ECSClient().CopyImage(request)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to copy image %q to region %q", id, destinationRegion)
+	}
+	return response.ImageId, nil
+}
@@ -0,0 +1,246 @@
+package ecs
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// describeInstancesBatchSize is the maximum number of instance ids of a DescribeInstances call.
+const describeInstancesBatchSize = 100
+
+// instanceCacheExpiry is how long an instance which hasn't been read is kept being synced.
+const instanceCacheExpiry = 30 * time.Minute
+
+// InstanceStateChange is a change of the state of a cached instance, the state of an instance
+// which doesn't exist is empty.
+type InstanceStateChange struct {
+	Region     string
+	InstanceID string
+	Previous   infrav1.InstanceState
+	Current    infrav1.InstanceState
+}
+
+// InstanceCache caches the instances the ACKMachines are reconciled against. Every sync
+// interval it describes all cached instances of a region with one DescribeInstances call per
+// 100 instances, so the reconciles of many ACKMachines share a few api calls instead of
+// describing their instances one by one. An instance which is not cached yet is described
+// right away and synced from then on, until it hasn't been read for a while.
+type InstanceCache struct {
+	log      logr.Logger
+	interval time.Duration
+	onChange []func(InstanceStateChange)
+
+	// newScope creates the scope of a region the api calls are made with
+	newScope func(region string) (scope.ECSScope, error)
+
+	mu      sync.Mutex
+	entries map[instanceKey]*instanceEntry
+	// scopes holds the scope of each region, the cache owns them rather than borrowing the
+	// scope of the reconcile which cached the first instance of the region
+	scopes map[string]scope.ECSScope
+}
+
+type instanceKey struct {
+	region string
+	id     string
+}
+
+type instanceEntry struct {
+	// instance is nil if the instance has disappeared since it was cached
+	instance *ecs.Instance
+	lastRead time.Time
+}
+
+// NewInstanceCache returns an instance cache syncing the cached instances every interval.
+func NewInstanceCache(log logr.Logger, interval time.Duration) *InstanceCache {
+	return &InstanceCache{
+		log:      log,
+		interval: interval,
+		newScope: newRegionScope(log),
+		entries:  map[instanceKey]*instanceEntry{},
+		scopes:   map[string]scope.ECSScope{},
+	}
+}
+
+// newRegionScope returns a function creating region scopes with the credentials of the manager.
+func newRegionScope(log logr.Logger) func(string) (scope.ECSScope, error) {
+	return func(region string) (scope.ECSScope, error) {
+		return scope.NewRegionScope(scope.RegionScopeParams{Logger: log, Region: region})
+	}
+}
+
+// regionScope returns the scope of the region, it is created on first use.
+func (c *InstanceCache) regionScope(region string) (scope.ECSScope, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ecsScope, ok := c.scopes[region]; ok {
+		return ecsScope, nil
+	}
+	ecsScope, err := c.newScope(region)
+	if err != nil {
+		return nil, err
+	}
+	c.scopes[region] = ecsScope
+	return ecsScope, nil
+}
+
+// OnStateChange registers a handler which is called for every instance whose state changed
+// during a sync. It must be registered before the cache is started.
+func (c *InstanceCache) OnStateChange(handler func(InstanceStateChange)) {
+	c.onChange = append(c.onChange, handler)
+}
+
+// Start syncs the cached instances until the stop channel is closed, it implements manager.Runnable.
+func (c *InstanceCache) Start(stop <-chan struct{}) error {
+	wait.Until(c.sync, c.interval, stop)
+	return nil
+}
+
+// instance returns the cached instance of the region or nil if it doesn't exist. Misses are
+// never served from the cache: an instance which has just been created may not be listed yet,
+// and reporting it missing until the next sync would let the reconcile create another one.
+func (c *InstanceCache) instance(region, id string) (*ecs.Instance, error) {
+	key := instanceKey{region: region, id: id}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && entry.instance != nil {
+		entry.lastRead = time.Now()
+		c.mu.Unlock()
+		return entry.instance, nil
+	}
+	c.mu.Unlock()
+
+	ecsScope, err := c.regionScope(region)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := describeInstances(ecsScope, []string{id})
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := instances[id]
+	if !ok {
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = &instanceEntry{instance: instance, lastRead: time.Now()}
+	return instance, nil
+}
+
+// invalidate drops the cached instance, e.g. after it has been modified, so that the next
+// read describes it again.
+func (c *InstanceCache) invalidate(region, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, instanceKey{region: region, id: id})
+}
+
+// sync describes all cached instances and notifies the handlers of the changed states.
+func (c *InstanceCache) sync() {
+	c.mu.Lock()
+	regions := map[string][]string{}
+	for key, entry := range c.entries {
+		if time.Since(entry.lastRead) > instanceCacheExpiry {
+			delete(c.entries, key)
+			continue
+		}
+		regions[key.region] = append(regions[key.region], key.id)
+	}
+	c.mu.Unlock()
+
+	var changes []InstanceStateChange
+	for region, ids := range regions {
+		ecsScope, err := c.regionScope(region)
+		if err != nil {
+			c.log.Error(err, "Failed to create the scope of the region", "region", region)
+			continue
+		}
+		sort.Strings(ids)
+		for start := 0; start < len(ids); start += describeInstancesBatchSize {
+			end := start + describeInstancesBatchSize
+			if end > len(ids) {
+				end = len(ids)
+			}
+			batch := ids[start:end]
+
+			instances, err := describeInstances(ecsScope, batch)
+			if err != nil {
+				c.log.Error(err, "Failed to sync cached instances", "region", region, "count", len(batch))
+				continue
+			}
+
+			c.mu.Lock()
+			for _, id := range batch {
+				entry, ok := c.entries[instanceKey{region: region, id: id}]
+				if !ok {
+					// invalidated during the sync
+					continue
+				}
+				previous, current := instanceState(entry.instance), instanceState(instances[id])
+				entry.instance = instances[id]
+				if previous != current {
+					changes = append(changes, InstanceStateChange{Region: region, InstanceID: id, Previous: previous, Current: current})
+				}
+			}
+			c.mu.Unlock()
+		}
+	}
+
+	for _, change := range changes {
+		c.log.V(2).Info("Cached instance changed state", "region", change.Region, "instance-id", change.InstanceID,
+			"previous", change.Previous, "current", change.Current)
+		for _, handler := range c.onChange {
+			handler(change)
+		}
+	}
+}
+
+// instanceState returns the state of the instance, or nothing if it doesn't exist.
+func instanceState(instance *ecs.Instance) infrav1.InstanceState {
+	if instance == nil {
+		return ""
+	}
+	return infrav1.InstanceState(instance.Status)
+}
+
+// describeInstances describes up to 100 instances by id, instances which don't exist are missing.
+func describeInstances(ecsScope scope.ECSScope, ids []string) (map[string]*ecs.Instance, error) {
+	encoded, err := json.Marshal(ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode instance ids")
+	}
+	request := ecs.CreateDescribeInstancesRequest()
+	request.RegionId = ecsScope.Region()
+	request.InstanceIds = string(encoded)
+	request.PageSize = requests.NewInteger(describeInstancesBatchSize)
+
+	var response *ecs.DescribeInstancesResponse
+	err = throttle.Do(ecsScope.Region(), throttle.ECS, func() (err error) {
+		response, err = ecsScope.ECSClient().DescribeInstances(request)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make(map[string]*ecs.Instance, len(response.Instances.Instance))
+	for i := range response.Instances.Instance {
+		instance := &response.Instances.Instance[i]
+		instances[instance.InstanceId] = instance
+	}
+	return instances, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ecs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	sdkecs "github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"k8s.io/klog/klogr"
+)
+
+func TestInstanceCache(t *testing.T) {
+	g := NewWithT(t)
+
+	// the fake ecs api lists the instance from the second DescribeInstances call on, as an
+	// instance which has just been created
+	describes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.ParseForm()).To(Succeed())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Form.Get("Action") {
+		case "DescribeInstances":
+			describes++
+			if describes == 1 {
+				_, _ = w.Write([]byte(`{"RequestId":"request","Instances":{"Instance":[]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"RequestId":"request","Instances":{"Instance":[{"InstanceId":"i-1","Status":"Running"}]}}`))
+		case "RunInstances":
+			// the client token of a retried creation returns the instance created before
+			_, _ = w.Write([]byte(`{"RequestId":"request","InstanceIdSets":{"InstanceIdSet":["i-1"]}}`))
+		default:
+			t.Errorf("unexpected action %q", r.Form.Get("Action"))
+		}
+	}))
+	defer server.Close()
+
+	ecsClient, err := sdkecs.NewClientWithAccessKey("cn-hangzhou", "id", "secret")
+	g.Expect(err).NotTo(HaveOccurred())
+	ecsClient.Domain = strings.TrimPrefix(server.URL, "http://")
+	vpcClient, err := vpc.NewClientWithAccessKey("cn-hangzhou", "id", "secret")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	regionScope, err := scope.NewRegionScope(scope.RegionScopeParams{
+		ACKClients: scope.ACKClients{ECS: ecsClient, VPC: vpcClient},
+		Region:     "cn-hangzhou",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cache := NewInstanceCache(klogr.New(), time.Minute)
+	cache.newScope = func(region string) (scope.ECSScope, error) {
+		g.Expect(region).To(Equal("cn-hangzhou"))
+		return regionScope, nil
+	}
+	svc := NewCachedService(regionScope, cache)
+	id := "i-1"
+
+	// a miss is not cached, the next read describes the instance again
+	instance, err := svc.InstanceIfExists(&id)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(instance).To(BeNil())
+	instance, err = svc.InstanceIfExists(&id)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(instance).NotTo(BeNil())
+	g.Expect(describes).To(Equal(2))
+
+	// an instance which exists is served from the cache
+	_, err = svc.InstanceIfExists(&id)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(describes).To(Equal(2))
+
+	// creating the instance drops it from the cache
+	machineScope := &scope.MachineScope{ACKMachine: &infrav1.ACKMachine{}}
+	created, err := svc.CreateInstance(machineScope, nil, "", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(created.Id).To(Equal(id))
+	_, err = svc.InstanceIfExists(&id)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(describes).To(Equal(3))
+}
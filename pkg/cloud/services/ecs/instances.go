@@ -1,21 +1,299 @@
 package ecs
 
 import (
-	"github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha3"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// use sdk to create ecs instance
-func (s *Service) RunInstances(request *ecs.RunInstancesRequest) (response *ecs.RunInstancesResponse, err error) {
-	return response, nil
+// InstanceIfExists returns the existing instance or nothing if it doesn't exist.
+func (s *Service) InstanceIfExists(id *string) (*infrav1.Instance, error) {
+	if id == nil {
+		s.scope.Info("Instance does not have an instance id")
+		return nil, nil
+	}
+
+	s.scope.V(2).Info("Looking for instance by id", "instance-id", *id)
+
+	var instance *ecs.Instance
+	if s.cache != nil {
+		var err error
+		if instance, err = s.cache.instance(s.scope.Region(), *id); err != nil {
+			return nil, errors.Wrapf(err, "failed to describe instance %q", *id)
+		}
+	} else {
+		instances, err := describeInstances(s.scope, []string{*id})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe instance %q", *id)
+		}
+		instance = instances[*id]
+	}
+	if instance == nil {
+		return nil, nil
+	}
+
+	return s.SDKToInstance(instance), nil
 }
 
-func (s *Service) CreateInstances() (*v1alpha3.Instance, error) {
-	input := v1alpha3.Instance{}
+// CreateInstance runs a single ecs instance for the ACKMachine with the given bootstrap data in
+// the resource group, the tags are applied to the instance and its disks. Only the ACKMachine of
+// the scope is used, so instances which don't belong to a cluster can be created as well.
+func (s *Service) CreateInstance(scope *scope.MachineScope, userData []byte, resourceGroupID string, tags infrav1.Tags) (*infrav1.Instance, error) {
+	s.scope.V(2).Info("Creating an instance for a machine")
+	spec := scope.ACKMachine.Spec
+
 	// make run instance request
-	// use SDK to run Instance
 	createRequest := ecs.CreateRunInstancesRequest()
-	// get client
+	createRequest.RegionId = spec.RegionId
+	createRequest.ZoneId = spec.ZoneId
+	// the zone chosen by the placement
+	if zoneID := scope.ACKMachine.Status.ZoneId; zoneID != "" {
+		createRequest.ZoneId = zoneID
+	}
+	createRequest.InstanceType = spec.InstanceType
+	// the instance type chosen from the acceptable instance types
+	if instanceType := scope.ACKMachine.Status.InstanceType; instanceType != "" {
+		createRequest.InstanceType = instanceType
+	}
+	createRequest.InstanceName = spec.InstanceName
+	createRequest.Description = spec.Description
+	createRequest.IoOptimized = spec.IoOptimized
+	createRequest.ResourceGroupId = resourceGroupID
+	createRequest.ImageId = spec.ImageId
+	// the image resolved from the image lookup
+	if imageID := scope.ACKMachine.Status.ImageId; imageID != "" {
+		createRequest.ImageId = imageID
+	}
+	createRequest.SecurityGroupId = spec.MachineNetworkSpec.SecurityGroupId
+	createRequest.VSwitchId = spec.MachineNetworkSpec.VSwitchId
+	if vswitchID := scope.ACKMachine.Status.VSwitchId; vswitchID != "" {
+		createRequest.VSwitchId = vswitchID
+	}
+	createRequest.PrivateIpAddress = spec.MachineNetworkSpec.PrivateIpAddress
+	createRequest.InternetChargeType = spec.MachineNetworkSpec.InternetChargeType
+	if bandwidth := spec.MachineNetworkSpec.InternetMaxBandwidthIn; bandwidth > 0 {
+		createRequest.InternetMaxBandwidthIn = requests.NewInteger64(bandwidth)
+	}
+	if bandwidth := spec.MachineNetworkSpec.InternetMaxBandwidthOut; bandwidth > 0 {
+		createRequest.InternetMaxBandwidthOut = requests.NewInteger64(bandwidth)
+	}
+	createRequest.UserData = base64.StdEncoding.EncodeToString(userData)
+	setSystemDisk(createRequest, &spec.MachineVolumeSpec.SystemDisk)
+	if dataDisks := sdkDataDisks(spec.MachineVolumeSpec.DataDisks); len(dataDisks) > 0 {
+		createRequest.DataDisk = &dataDisks
+	}
+	// the tags are applied to the instance and its disks
+	var sdkTags []ecs.RunInstancesTag
+	for _, key := range tags.Keys() {
+		sdkTags = append(sdkTags, ecs.RunInstancesTag{Key: key, Value: tags[key]})
+	}
+	if len(sdkTags) > 0 {
+		createRequest.Tag = &sdkTags
+	}
+
+	// fields set explicitly on the ACKMachine override the ones of the launch template
+	if spec.LaunchTemplate != nil {
+		template, err := s.ResolveLaunchTemplate(spec.LaunchTemplate)
+		if err != nil {
+			return nil, err
+		}
+		createRequest.LaunchTemplateId = template.ID
+		createRequest.LaunchTemplateVersion = requests.NewInteger64(template.Version)
+		scope.SetLaunchTemplate(template)
+	}
+
+	if spec.InstanceChargeType != "" {
+		createRequest.InstanceChargeType = spec.InstanceChargeType
+	}
+	if spec.InstanceChargeType == string(infrav1.InstanceChargeTypePrePaid) {
+		createRequest.Period = requests.NewInteger64(spec.Period)
+		createRequest.PeriodUnit = spec.PeriodUnit
+		createRequest.AutoRenew = requests.NewBoolean(spec.AutoRenew)
+		if spec.AutoRenew {
+			createRequest.AutoRenewPeriod = requests.NewInteger64(spec.AutoRenewPeriod)
+		}
+	}
+
+	if spot := spec.SpotOptions; spot != nil {
+		// spot instances are only available as pay-as-you-go instances
+		createRequest.InstanceChargeType = string(infrav1.InstanceChargeTypePostPaid)
+		createRequest.SpotStrategy = string(spot.SpotStrategy)
+		if spot.MaxPrice != nil {
+			createRequest.SpotPriceLimit = requests.Float(*spot.MaxPrice)
+		}
+		if spot.SpotDuration != nil {
+			createRequest.SpotDuration = requests.NewInteger64(*spot.SpotDuration)
+		}
+	}
+
+	createRequest.ClientToken = clientToken(scope.ACKMachine, createRequest)
+
+	// use SDK to run Instance
+	var response *ecs.RunInstancesResponse
+	err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+		response, err = s.scope.ECSClient().RunInstances(createRequest)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to run instance for ACKMachine %s/%s", scope.Namespace(), scope.Name())
+	}
+	if len(response.InstanceIdSets.InstanceIdSet) == 0 {
+		return nil, errors.Errorf("no instance returned for ACKMachine %s/%s", scope.Namespace(), scope.Name())
+	}
+	// a read racing the creation may have described the instance before it was listed
+	s.invalidateInstance(response.InstanceIdSets.InstanceIdSet[0])
+
+	instance := &infrav1.Instance{
+		Id:                 response.InstanceIdSets.InstanceIdSet[0],
+		InstanceName:       spec.InstanceName,
+		State:              infrav1.InstanceStatePending,
+		RegionId:           spec.RegionId,
+		ZoneId:             createRequest.ZoneId,
+		InstanceType:       createRequest.InstanceType,
+		ImageId:            createRequest.ImageId,
+		InstanceChargeType: createRequest.InstanceChargeType,
+		VpcAttributes: infrav1.VpcAttributes{
+			VSwitchId: createRequest.VSwitchId,
+		},
+	}
+	if spec.SpotOptions != nil {
+		instance.SpotStrategy = spec.SpotOptions.SpotStrategy
+	}
+	return instance, nil
+}
+
+// clientToken returns the idempotency token of running the instance of the ACKMachine, so
+// retrying after the ProviderID failed to be persisted returns the instance created before
+// instead of creating another one. It covers the instance type and zone, as a placement chosen
+// after a stock-out is another request. An ACKMachine without UID gets no token.
+func clientToken(ackMachine *infrav1.ACKMachine, request *ecs.RunInstancesRequest) string {
+	if ackMachine.UID == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{string(ackMachine.UID), request.InstanceType, request.ZoneId}, "/")))
+	return hex.EncodeToString(sum[:])
+}
+
+// ConvertToPostPaid switches the billing method of a PrePaid instance and its data disks
+// to pay-as-you-go, so the instance can be released before it expires.
+func (s *Service) ConvertToPostPaid(id string) error {
+	s.scope.V(2).Info("Converting PrePaid instance to PostPaid", "instance-id", id)
+
+	ids, err := json.Marshal([]string{id})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode instance ids")
+	}
+	request := ecs.CreateModifyInstanceChargeTypeRequest()
+	request.RegionId = s.scope.Region()
+	request.InstanceIds = string(ids)
+	request.InstanceChargeType = string(infrav1.InstanceChargeTypePostPaid)
+	request.IncludeDataDisks = requests.NewBoolean(true)
+	request.AutoPay = requests.NewBoolean(true)
+
+	if err := throttle.Do(s.scope.Region(), throttle.ECS, func() error {
+		_, err := s.scope.ECSClient().ModifyInstanceChargeType(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to convert instance %q to PostPaid", id)
+	}
+	s.invalidateInstance(id)
+	return nil
+}
+
+// TerminateInstance releases the instance, a running instance is stopped forcibly.
+func (s *Service) TerminateInstance(id string) error {
+	s.scope.V(2).Info("Attempting to terminate instance", "instance-id", id)
+
+	request := ecs.CreateDeleteInstanceRequest()
+	request.RegionId = s.scope.Region()
+	request.InstanceId = id
+	request.Force = requests.NewBoolean(true)
+
+	if err := throttle.Do(s.scope.Region(), throttle.ECS, func() error {
+		_, err := s.scope.ECSClient().DeleteInstance(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to terminate instance with id %q", id)
+	}
+	s.invalidateInstance(id)
+
+	s.scope.V(2).Info("Terminated instance", "instance-id", id)
+	return nil
+}
+
+// invalidateInstance drops a modified instance from the instance cache.
+func (s *Service) invalidateInstance(id string) {
+	if s.cache != nil {
+		s.cache.invalidate(s.scope.Region(), id)
+	}
+}
+
+// SDKToInstance converts an ecs SDK instance to the provider instance type.
+func (s *Service) SDKToInstance(v *ecs.Instance) *infrav1.Instance {
+	i := &infrav1.Instance{
+		Id:                  v.InstanceId,
+		InstanceName:        v.InstanceName,
+		State:               infrav1.InstanceState(v.Status),
+		RegionId:            v.RegionId,
+		ZoneId:              v.ZoneId,
+		ResourceGroupId:     v.ResourceGroupId,
+		InstanceType:        v.InstanceType,
+		InstanceTypeFamily:  v.InstanceTypeFamily,
+		ImageId:             v.ImageId,
+		KeyPairName:         v.KeyPairName,
+		SecurityGroupIDs:    v.SecurityGroupIds.SecurityGroupId,
+		CPU:                 int64(v.Cpu),
+		Memory:              int64(v.Memory),
+		OSType:              v.OSType,
+		OSName:              v.OSName,
+		OSNameEn:            v.OSNameEn,
+		InstanceNetworkType: v.InstanceNetworkType,
+		InnerIpAddress:      v.InnerIpAddress.IpAddress,
+		PublicIpAddress:     v.PublicIpAddress.IpAddress,
+		DeviceAvailable:     &v.DeviceAvailable,
+		InstanceChargeType:  v.InstanceChargeType,
+		SpotStrategy:        infrav1.SpotStrategy(v.SpotStrategy),
+		EipAddress: infrav1.EipAddress{
+			AllocationId:       v.EipAddress.AllocationId,
+			IpAddress:          v.EipAddress.IpAddress,
+			Bandwidth:          int64(v.EipAddress.Bandwidth),
+			InternetChargeType: v.EipAddress.InternetChargeType,
+		},
+		VpcAttributes: infrav1.VpcAttributes{
+			NatIpAddress:     v.VpcAttributes.NatIpAddress,
+			PrivateIpAddress: v.VpcAttributes.PrivateIpAddress.IpAddress,
+			VSwitchId:        v.VpcAttributes.VSwitchId,
+			VpcId:            v.VpcAttributes.VpcId,
+		},
+	}
+
+	if v.InstanceChargeType == string(infrav1.InstanceChargeTypePrePaid) && v.ExpiredTime != "" {
+		expiredTime, err := time.Parse(expiredTimeLayout, v.ExpiredTime)
+		if err != nil {
+			s.scope.Info("Failed to parse instance expired time", "instance-id", v.InstanceId, "expired-time", v.ExpiredTime)
+		} else {
+			i.ExpiredTime = &metav1.Time{Time: expiredTime}
+		}
+	}
+
+	for _, lock := range v.OperationLocks.LockReason {
+		i.LockReasons = append(i.LockReasons, lock.LockReason)
+	}
+	i.Tags = infrav1.Tags{}
+	for _, tag := range v.Tags.Tag {
+		i.Tags[tag.TagKey] = tag.TagValue
+	}
 
-	return nil, nil
+	return i
 }
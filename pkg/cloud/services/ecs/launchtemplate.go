@@ -0,0 +1,58 @@
+package ecs
+
+import (
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/pkg/errors"
+)
+
+// ResolveLaunchTemplate looks up the referenced launch template and returns the version to
+// create instances from, the default version is used if the reference has no version.
+func (s *Service) ResolveLaunchTemplate(ref *infrav1.LaunchTemplateReference) (*infrav1.LaunchTemplateStatus, error) {
+	if ref == nil {
+		return nil, nil
+	}
+
+	request := ecs.CreateDescribeLaunchTemplatesRequest()
+	request.RegionId = s.scope.Region()
+	if ref.ID != "" {
+		request.LaunchTemplateId = &[]string{ref.ID}
+	} else {
+		request.LaunchTemplateName = &[]string{ref.Name}
+	}
+
+	var response *ecs.DescribeLaunchTemplatesResponse
+	err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+		response, err = s.scope.ECSClient().DescribeLaunchTemplates(request)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe launch template %q", launchTemplateName(ref))
+	}
+	if len(response.LaunchTemplateSets.LaunchTemplateSet) == 0 {
+		return nil, errors.Errorf("launch template %q not found", launchTemplateName(ref))
+	}
+	template := response.LaunchTemplateSets.LaunchTemplateSet[0]
+
+	resolved := &infrav1.LaunchTemplateStatus{
+		ID:      template.LaunchTemplateId,
+		Name:    template.LaunchTemplateName,
+		Version: template.DefaultVersionNumber,
+	}
+	if ref.Version != nil {
+		if *ref.Version > template.LatestVersionNumber {
+			return nil, errors.Errorf("launch template %q has no version %d, the latest version is %d",
+				launchTemplateName(ref), *ref.Version, template.LatestVersionNumber)
+		}
+		resolved.Version = *ref.Version
+	}
+	return resolved, nil
+}
+
+func launchTemplateName(ref *infrav1.LaunchTemplateReference) string {
+	if ref.ID != "" {
+		return ref.ID
+	}
+	return ref.Name
+}
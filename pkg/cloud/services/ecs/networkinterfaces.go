@@ -0,0 +1,117 @@
+package ecs
+
+import (
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/pkg/errors"
+)
+
+// CreateNetworkInterface creates a secondary elastic network interface for the ACKMachine and returns its ID.
+func (s *Service) CreateNetworkInterface(scope *scope.MachineScope, spec *infrav1.NetworkInterface) (string, error) {
+	s.scope.V(2).Info("Creating network interface", "vswitch-id", spec.VSwitchId)
+
+	request := ecs.CreateCreateNetworkInterfaceRequest()
+	request.RegionId = s.scope.Region()
+	request.VSwitchId = spec.VSwitchId
+	request.NetworkInterfaceName = spec.NetworkInterfaceName
+	request.Description = spec.Description
+	request.PrimaryIpAddress = spec.PrimaryIpAddress
+	request.SecurityGroupId = spec.SecurityGroupId
+	request.ResourceGroupId = scope.ResourceGroupID()
+	if len(spec.SecurityGroupIds) > 0 {
+		securityGroupIds := spec.SecurityGroupIds
+		request.SecurityGroupIds = &securityGroupIds
+	}
+	tags := scope.Tags()
+	var sdkTags []ecs.CreateNetworkInterfaceTag
+	for _, key := range tags.Keys() {
+		sdkTags = append(sdkTags, ecs.CreateNetworkInterfaceTag{Key: key, Value: tags[key]})
+	}
+	if len(sdkTags) > 0 {
+		request.Tag = &sdkTags
+	}
+
+	var response *ecs.CreateNetworkInterfaceResponse
+	err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+		response, err = s.scope.ECSClient().CreateNetworkInterface(request)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create network interface")
+	}
+
+	s.scope.V(2).Info("Created network interface", "network-interface-id", response.NetworkInterfaceId)
+	return response.NetworkInterfaceId, nil
+}
+
+// AttachNetworkInterface attaches the elastic network interface to the instance.
+func (s *Service) AttachNetworkInterface(id, instanceID string) error {
+	s.scope.V(2).Info("Attaching network interface", "network-interface-id", id, "instance-id", instanceID)
+
+	request := ecs.CreateAttachNetworkInterfaceRequest()
+	request.RegionId = s.scope.Region()
+	request.NetworkInterfaceId = id
+	request.InstanceId = instanceID
+
+	if err := throttle.Do(s.scope.Region(), throttle.ECS, func() error {
+		_, err := s.scope.ECSClient().AttachNetworkInterface(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to attach network interface %q to instance %q", id, instanceID)
+	}
+	return nil
+}
+
+// DeleteNetworkInterface deletes the elastic network interface, it has to be detached first.
+func (s *Service) DeleteNetworkInterface(id string) error {
+	s.scope.V(2).Info("Deleting network interface", "network-interface-id", id)
+
+	request := ecs.CreateDeleteNetworkInterfaceRequest()
+	request.RegionId = s.scope.Region()
+	request.NetworkInterfaceId = id
+
+	if err := throttle.Do(s.scope.Region(), throttle.ECS, func() error {
+		_, err := s.scope.ECSClient().DeleteNetworkInterface(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to delete network interface %q", id)
+	}
+	return nil
+}
+
+// DescribeNetworkInterfaces returns the elastic network interfaces with the given IDs,
+// network interfaces which do not exist are omitted.
+func (s *Service) DescribeNetworkInterfaces(ids []string) ([]infrav1.NetworkInterfaceStatus, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	request := ecs.CreateDescribeNetworkInterfacesRequest()
+	request.RegionId = s.scope.Region()
+	request.NetworkInterfaceId = &ids
+	request.PageSize = requests.NewInteger(maxDescribePageSize)
+
+	var response *ecs.DescribeNetworkInterfacesResponse
+	err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+		response, err = s.scope.ECSClient().DescribeNetworkInterfaces(request)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe network interfaces %v", ids)
+	}
+
+	nics := make([]infrav1.NetworkInterfaceStatus, 0, len(response.NetworkInterfaceSets.NetworkInterfaceSet))
+	for _, nic := range response.NetworkInterfaceSets.NetworkInterfaceSet {
+		nics = append(nics, infrav1.NetworkInterfaceStatus{
+			ID:               nic.NetworkInterfaceId,
+			Name:             nic.NetworkInterfaceName,
+			PrivateIpAddress: nic.PrivateIpAddress,
+			MacAddress:       nic.MacAddress,
+			InstanceId:       nic.InstanceId,
+		})
+	}
+	return nics, nil
+}
@@ -0,0 +1,24 @@
+package ecs
+
+import (
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/pkg/errors"
+)
+
+// DeleteSecurityGroup deletes the security group.
+func (s *Service) DeleteSecurityGroup(id string) error {
+	s.scope.V(2).Info("Deleting security group", "security-group-id", id)
+
+	request := ecs.CreateDeleteSecurityGroupRequest()
+	request.RegionId = s.scope.Region()
+	request.SecurityGroupId = id
+
+	if err := throttle.Do(s.scope.Region(), throttle.ECS, func() error {
+		_, err := s.scope.ECSClient().DeleteSecurityGroup(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to delete security group %q", id)
+	}
+	return nil
+}
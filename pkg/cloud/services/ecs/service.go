@@ -1,8 +1,29 @@
 package ecs
 
+import (
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+)
+
+// Service holds a collection of interfaces.
+// The interfaces are broken down like this to group functions together.
+// One alternative is to have a large list of functions from the ecs client.
 type Service struct {
+	scope scope.ECSScope
+	// cache is the shared instance cache, the instances are described directly without one
+	cache *InstanceCache
+}
+
+// NewService returns a new service given the ecs api client.
+func NewService(ecsScope scope.ECSScope) *Service {
+	return &Service{
+		scope: ecsScope,
+	}
 }
 
-func NewService() *Service {
-	return &Service{}
+// NewCachedService returns a new service reading the instances from the shared instance cache.
+func NewCachedService(ecsScope scope.ECSScope, cache *InstanceCache) *Service {
+	return &Service{
+		scope: ecsScope,
+		cache: cache,
+	}
 }
@@ -1,4 +1,30 @@
 package ecs
 
-type ECSInstance struct {
-}
+const (
+	// maxDescribePageSize is the largest page size accepted by the describe apis
+	maxDescribePageSize = 100
+
+	// diskResizeTypeOnline resizes a disk without restarting the instance
+	diskResizeTypeOnline = "online"
+
+	// expiredTimeLayout is the time format of the instance expired time, e.g. 2017-12-10T04:04Z
+	expiredTimeLayout = "2006-01-02T15:04Z"
+
+	// availableResourceInstanceType queries the availability of instance types
+	availableResourceInstanceType = "InstanceType"
+	// availableResourceTypeInstance restricts the availability query to ecs instances
+	availableResourceTypeInstance = "instance"
+	// availableResourceStatusAvailable is the status of an available zone or resource
+	availableResourceStatusAvailable = "Available"
+	// availableResourceStatusCategoryWithStock is the status category of a resource with stock
+	availableResourceStatusCategoryWithStock = "WithStock"
+
+	// ImageStatusAvailable is the status of an image instances can be created from
+	ImageStatusAvailable = "Available"
+	// ImageStatusCreateFailed is the status of an image which failed to be created or copied
+	ImageStatusCreateFailed = "CreateFailed"
+	// imageStatusAll matches the images in any status
+	imageStatusAll = "Creating,Waiting,Available,UnAvailable,CreateFailed"
+	// ImageTagKubernetesVersion is the tag key of the Kubernetes version an image is built for
+	ImageTagKubernetesVersion = "kubernetes-version"
+)
@@ -0,0 +1,256 @@
+package gc
+
+import (
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	ecssvc "github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	vpcsvc "github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/vpc"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// pageSize is the maximum page size of the describe calls
+	pageSize = 100
+
+	// diskStatusAvailable is the status of a disk which isn't attached to an instance
+	diskStatusAvailable = "Available"
+	// eipStatusInUse is the status of an elastic IP address associated with an instance
+	eipStatusInUse = "InUse"
+)
+
+// Resource is a resource tagged as owned by a cluster of the provider.
+type Resource struct {
+	// Type is the tag resource type of the resource, e.g. instance or disk.
+	Type string
+	ID   string
+	// ClusterNamespace is the namespace of the cluster owning the resource.
+	ClusterNamespace string
+	// ClusterName is the name of the cluster owning the resource.
+	ClusterName string
+	// Attached is true if the resource is attached to an instance and is released with it.
+	Attached bool
+	// Retained is true for a disk which is kept when its instance is released.
+	Retained bool
+}
+
+// OwnedResources returns the instances, disks, security groups and elastic IP addresses of
+// the region which are tagged as owned by a cluster of the provider.
+func (s *Service) OwnedResources() ([]Resource, error) {
+	var resources []Resource
+	for _, list := range []func() ([]Resource, error){
+		s.ownedInstances,
+		s.ownedDisks,
+		s.ownedSecurityGroups,
+		s.ownedEIPs,
+	} {
+		owned, err := list()
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, owned...)
+	}
+	return resources, nil
+}
+
+// DeleteResource deletes an owned resource, instances are released forcibly.
+func (s *Service) DeleteResource(resource Resource) error {
+	s.scope.V(2).Info("Deleting orphaned resource", "resource-type", resource.Type, "id", resource.ID)
+
+	var err error
+	switch resource.Type {
+	case ecssvc.ResourceTypeInstance:
+		request := ecs.CreateDeleteInstanceRequest()
+		request.RegionId = s.scope.Region()
+		request.InstanceId = resource.ID
+		request.Force = requests.NewBoolean(true)
+		err = throttle.Do(s.scope.Region(), throttle.ECS, func() error {
+			_, err := s.scope.ECS.DeleteInstance(request)
+			return err
+		})
+	case ecssvc.ResourceTypeDisk:
+		request := ecs.CreateDeleteDiskRequest()
+		request.RegionId = s.scope.Region()
+		request.DiskId = resource.ID
+		err = throttle.Do(s.scope.Region(), throttle.ECS, func() error {
+			_, err := s.scope.ECS.DeleteDisk(request)
+			return err
+		})
+	case ecssvc.ResourceTypeSecurityGroup:
+		request := ecs.CreateDeleteSecurityGroupRequest()
+		request.RegionId = s.scope.Region()
+		request.SecurityGroupId = resource.ID
+		err = throttle.Do(s.scope.Region(), throttle.ECS, func() error {
+			_, err := s.scope.ECS.DeleteSecurityGroup(request)
+			return err
+		})
+	case vpcsvc.ResourceTypeEIP:
+		request := vpc.CreateReleaseEipAddressRequest()
+		request.RegionId = s.scope.Region()
+		request.AllocationId = resource.ID
+		err = throttle.Do(s.scope.Region(), throttle.VPC, func() error {
+			_, err := s.scope.VPC.ReleaseEipAddress(request)
+			return err
+		})
+	default:
+		return errors.Errorf("unsupported resource type %q", resource.Type)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete %s %q", resource.Type, resource.ID)
+	}
+	return nil
+}
+
+func (s *Service) ownedInstances() ([]Resource, error) {
+	var resources []Resource
+	for page, count := 1, 0; ; page++ {
+		request := ecs.CreateDescribeInstancesRequest()
+		request.RegionId = s.scope.Region()
+		request.PageSize = requests.NewInteger(pageSize)
+		request.PageNumber = requests.NewInteger(page)
+
+		var response *ecs.DescribeInstancesResponse
+		err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+			response, err = s.scope.ECS.DescribeInstances(request)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to describe instances")
+		}
+		for _, instance := range response.Instances.Instance {
+			tags := infrav1.Tags{}
+			for _, tag := range instance.Tags.Tag {
+				tags[tag.TagKey] = tag.TagValue
+			}
+			if namespace, name, ok := ownerCluster(tags); ok {
+				resources = append(resources, Resource{Type: ecssvc.ResourceTypeInstance, ID: instance.InstanceId, ClusterNamespace: namespace, ClusterName: name})
+			}
+		}
+		count += len(response.Instances.Instance)
+		if len(response.Instances.Instance) == 0 || count >= response.TotalCount {
+			return resources, nil
+		}
+	}
+}
+
+func (s *Service) ownedDisks() ([]Resource, error) {
+	var resources []Resource
+	for page, count := 1, 0; ; page++ {
+		request := ecs.CreateDescribeDisksRequest()
+		request.RegionId = s.scope.Region()
+		request.PageSize = requests.NewInteger(pageSize)
+		request.PageNumber = requests.NewInteger(page)
+
+		var response *ecs.DescribeDisksResponse
+		err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+			response, err = s.scope.ECS.DescribeDisks(request)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to describe disks")
+		}
+		for _, disk := range response.Disks.Disk {
+			tags := infrav1.Tags{}
+			for _, tag := range disk.Tags.Tag {
+				tags[tag.TagKey] = tag.TagValue
+			}
+			if namespace, name, ok := ownerCluster(tags); ok {
+				resources = append(resources, Resource{
+					Type:             ecssvc.ResourceTypeDisk,
+					ID:               disk.DiskId,
+					ClusterNamespace: namespace,
+					ClusterName:      name,
+					Attached:         disk.Status != diskStatusAvailable,
+					Retained:         !disk.DeleteWithInstance,
+				})
+			}
+		}
+		count += len(response.Disks.Disk)
+		if len(response.Disks.Disk) == 0 || count >= response.TotalCount {
+			return resources, nil
+		}
+	}
+}
+
+func (s *Service) ownedSecurityGroups() ([]Resource, error) {
+	var resources []Resource
+	for page, count := 1, 0; ; page++ {
+		request := ecs.CreateDescribeSecurityGroupsRequest()
+		request.RegionId = s.scope.Region()
+		request.PageSize = requests.NewInteger(pageSize)
+		request.PageNumber = requests.NewInteger(page)
+
+		var response *ecs.DescribeSecurityGroupsResponse
+		err := throttle.Do(s.scope.Region(), throttle.ECS, func() (err error) {
+			response, err = s.scope.ECS.DescribeSecurityGroups(request)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to describe security groups")
+		}
+		for _, group := range response.SecurityGroups.SecurityGroup {
+			tags := infrav1.Tags{}
+			for _, tag := range group.Tags.Tag {
+				tags[tag.TagKey] = tag.TagValue
+			}
+			if namespace, name, ok := ownerCluster(tags); ok {
+				resources = append(resources, Resource{Type: ecssvc.ResourceTypeSecurityGroup, ID: group.SecurityGroupId, ClusterNamespace: namespace, ClusterName: name})
+			}
+		}
+		count += len(response.SecurityGroups.SecurityGroup)
+		if len(response.SecurityGroups.SecurityGroup) == 0 || count >= response.TotalCount {
+			return resources, nil
+		}
+	}
+}
+
+func (s *Service) ownedEIPs() ([]Resource, error) {
+	var resources []Resource
+	for page, count := 1, 0; ; page++ {
+		request := vpc.CreateDescribeEipAddressesRequest()
+		request.RegionId = s.scope.Region()
+		request.PageSize = requests.NewInteger(pageSize)
+		request.PageNumber = requests.NewInteger(page)
+
+		var response *vpc.DescribeEipAddressesResponse
+		err := throttle.Do(s.scope.Region(), throttle.VPC, func() (err error) {
+			response, err = s.scope.VPC.DescribeEipAddresses(request)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to describe elastic IP addresses")
+		}
+		for _, eip := range response.EipAddresses.EipAddress {
+			tags := infrav1.Tags{}
+			for _, tag := range eip.Tags.Tag {
+				tags[tag.Key] = tag.Value
+			}
+			if namespace, name, ok := ownerCluster(tags); ok {
+				resources = append(resources, Resource{
+					Type:             vpcsvc.ResourceTypeEIP,
+					ID:               eip.AllocationId,
+					ClusterNamespace: namespace,
+					ClusterName:      name,
+					Attached:         eip.Status == eipStatusInUse,
+				})
+			}
+		}
+		count += len(response.EipAddresses.EipAddress)
+		if len(response.EipAddresses.EipAddress) == 0 || count >= response.TotalCount {
+			return resources, nil
+		}
+	}
+}
+
+// ownerCluster returns the namespace and name of the cluster owning a resource with the tags,
+// resources shared with a cluster are never owned.
+func ownerCluster(tags infrav1.Tags) (string, string, bool) {
+	for _, key := range tags.Keys() {
+		if namespace, name, ok := infrav1.ParseClusterTagKey(key); ok && tags[key] == string(infrav1.ResourceLifecycleOwned) {
+			return namespace, name, true
+		}
+	}
+	return "", "", false
+}
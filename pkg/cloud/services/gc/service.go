@@ -0,0 +1,19 @@
+package gc
+
+import (
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+)
+
+// Service holds a collection of interfaces.
+// The interfaces are broken down like this to group functions together.
+// One alternative is to have a large list of functions from the ecs and vpc clients.
+type Service struct {
+	scope *scope.RegionScope
+}
+
+// NewService returns a new service given the ecs and vpc api clients of the region.
+func NewService(regionScope *scope.RegionScope) *Service {
+	return &Service{
+		scope: regionScope,
+	}
+}
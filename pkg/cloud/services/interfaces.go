@@ -1,9 +1,69 @@
 package services
 
 import (
-	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/ecs"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/services/gc"
 )
 
+// ECSMachineInterface encapsulates the methods exposed to the machine actuator
 type ECSMachineInterface interface {
-	RunInstances(request *ecs.RunInstancesRequest) (response *ecs.RunInstancesResponse, err error)
+	InstanceIfExists(id *string) (*infrav1.Instance, error)
+	CreateInstance(scope *scope.MachineScope, userData []byte, resourceGroupID string, tags infrav1.Tags) (*infrav1.Instance, error)
+	ConvertToPostPaid(id string) error
+	TerminateInstance(id string) error
+	AvailableZones(scope *scope.MachineScope, instanceType string) ([]string, error)
+	LookupImage(lookup *infrav1.ImageLookup) (string, error)
+	GetInstanceVolumes(id string) ([]infrav1.Volume, error)
+	ResizeDisk(id string, size int64) error
+	CreateNetworkInterface(scope *scope.MachineScope, spec *infrav1.NetworkInterface) (string, error)
+	AttachNetworkInterface(id, instanceID string) error
+	DeleteNetworkInterface(id string) error
+	DescribeNetworkInterfaces(ids []string) ([]infrav1.NetworkInterfaceStatus, error)
+	EnsureTags(resourceType string, ids []string, tags infrav1.Tags, removed []string) error
+}
+
+// VPCInterface encapsulates the methods exposed to the machine and cluster actuators
+type VPCInterface interface {
+	AllocateEIP(scope *scope.MachineScope, spec *infrav1.EIPSpec) (*infrav1.EIPStatus, error)
+	AssociateEIP(allocationID, instanceID string) error
+	ReleaseEIP(allocationID string) error
+	DescribeVSwitches(ids []string) ([]infrav1.VSwitch, error)
+	CheckNetwork(vpcID string, vswitchIDs []string) (string, error)
+	EnsureTags(resourceType string, ids []string, tags infrav1.Tags, removed []string) error
+	TaggedResources(resourceType string, tags infrav1.Tags) ([]string, error)
+	DeleteNatGateway(id string) error
+	DeleteVSwitch(id string) error
+	DeleteVPC(id string) error
+}
+
+// ECSClusterInterface encapsulates the methods exposed to the cluster actuator
+type ECSClusterInterface interface {
+	TaggedResources(resourceType string, tags infrav1.Tags) ([]string, error)
+	DeleteSecurityGroup(id string) error
+}
+
+// SLBInterface encapsulates the methods exposed to the cluster actuator
+type SLBInterface interface {
+	CheckLoadBalancer(address string) (string, string, error)
+	TaggedLoadBalancers(tags infrav1.Tags) ([]string, error)
+	DeleteLoadBalancer(id string) error
+}
+
+// ECSImageInterface encapsulates the methods exposed to the image actuator
+type ECSImageInterface interface {
+	ImageStatus(id string) (string, error)
+	CopyImage(id, destinationRegion string, spec *ecs.ImageSpec) (string, error)
+}
+
+// ResourceManagerInterface encapsulates the methods exposed to the cluster and machine actuators
+type ResourceManagerInterface interface {
+	CheckResourceGroup(id string) error
+}
+
+// GCInterface encapsulates the methods exposed to the orphan collector
+type GCInterface interface {
+	OwnedResources() ([]gc.Resource, error)
+	DeleteResource(resource gc.Resource) error
 }
@@ -0,0 +1,38 @@
+package resourcemanager
+
+import (
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/aliyunerrors"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/resourcemanager"
+	"github.com/pkg/errors"
+)
+
+const (
+	// resourceGroupStatusOK is the status of a resource group resources can be created in
+	resourceGroupStatusOK = "OK"
+)
+
+// CheckResourceGroup returns an error if the resource group doesn't exist or resources can't
+// be created in it.
+func (s *Service) CheckResourceGroup(id string) error {
+	s.scope.V(2).Info("Checking resource group", "resource-group-id", id)
+
+	request := resourcemanager.CreateGetResourceGroupRequest()
+	request.ResourceGroupId = id
+
+	var response *resourcemanager.GetResourceGroupResponse
+	err := throttle.Do(s.scope.Region(), throttle.ResourceManager, func() (err error) {
+		response, err = s.scope.ResourceManager.GetResourceGroup(request)
+		return err
+	})
+	if err != nil {
+		if aliyunerrors.IsNotFound(err) {
+			return errors.Errorf("resource group %q does not exist", id)
+		}
+		return errors.Wrapf(err, "failed to get resource group %q", id)
+	}
+	if status := response.ResourceGroup.Status; status != resourceGroupStatusOK {
+		return errors.Errorf("resource group %q is in status %q", id, status)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package slb
+
+import (
+	"fmt"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/slb"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ResourceTypeLoadBalancer is the tag resource type of load balancers
+	ResourceTypeLoadBalancer = "instance"
+	// loadBalancerStatusActive is the status of a load balancer which forwards traffic
+	loadBalancerStatusActive = "active"
+)
+
+// CheckLoadBalancer returns the ID of the load balancer with the address, and why it can't
+// be used yet, or nothing if it is active.
+func (s *Service) CheckLoadBalancer(address string) (string, string, error) {
+	request := slb.CreateDescribeLoadBalancersRequest()
+	request.RegionId = s.scope.Region()
+	request.Address = address
+
+	var response *slb.DescribeLoadBalancersResponse
+	err := throttle.Do(s.scope.Region(), throttle.SLB, func() (err error) {
+		response, err = s.scope.SLB.DescribeLoadBalancers(request)
+		return err
+	})
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to describe the load balancer of address %q", address)
+	}
+	if len(response.LoadBalancers.LoadBalancer) == 0 {
+		return "", fmt.Sprintf("no load balancer with address %s found", address), nil
+	}
+	loadBalancer := response.LoadBalancers.LoadBalancer[0]
+	if loadBalancer.LoadBalancerStatus != loadBalancerStatusActive {
+		return loadBalancer.LoadBalancerId, fmt.Sprintf("load balancer %s is %s", loadBalancer.LoadBalancerId, loadBalancer.LoadBalancerStatus), nil
+	}
+	return loadBalancer.LoadBalancerId, "", nil
+}
+
+// TaggedLoadBalancers returns the IDs of the load balancers which carry all the tags.
+func (s *Service) TaggedLoadBalancers(tags infrav1.Tags) ([]string, error) {
+	request := slb.CreateListTagResourcesRequest()
+	request.RegionId = s.scope.Region()
+	request.ResourceType = ResourceTypeLoadBalancer
+	var sdkTags []slb.ListTagResourcesTag
+	for _, key := range tags.Keys() {
+		sdkTags = append(sdkTags, slb.ListTagResourcesTag{Key: key, Value: tags[key]})
+	}
+	request.Tag = &sdkTags
+
+	var ids []string
+	seen := map[string]bool{}
+	for {
+		var response *slb.ListTagResourcesResponse
+		err := throttle.Do(s.scope.Region(), throttle.SLB, func() (err error) {
+			response, err = s.scope.SLB.ListTagResources(request)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list tagged load balancers")
+		}
+		for _, resource := range response.TagResources.TagResource {
+			if !seen[resource.ResourceId] {
+				seen[resource.ResourceId] = true
+				ids = append(ids, resource.ResourceId)
+			}
+		}
+		if response.NextToken == "" {
+			return ids, nil
+		}
+		request.NextToken = response.NextToken
+	}
+}
+
+// DeleteLoadBalancer deletes the load balancer together with its listeners.
+func (s *Service) DeleteLoadBalancer(id string) error {
+	s.scope.V(2).Info("Deleting load balancer", "load-balancer-id", id)
+
+	request := slb.CreateDeleteLoadBalancerRequest()
+	request.RegionId = s.scope.Region()
+	request.LoadBalancerId = id
+
+	if err := throttle.Do(s.scope.Region(), throttle.SLB, func() error {
+		_, err := s.scope.SLB.DeleteLoadBalancer(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to delete load balancer %q", id)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package vpc
+
+import (
+	"strconv"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// eipInstanceTypeECS is the instance type to associate an elastic IP address with an ECS instance
+	eipInstanceTypeECS = "EcsInstance"
+)
+
+// AllocateEIP allocates a new elastic IP address with the given settings for the ACKMachine.
+func (s *Service) AllocateEIP(scope *scope.MachineScope, spec *infrav1.EIPSpec) (*infrav1.EIPStatus, error) {
+	s.scope.V(2).Info("Allocating elastic IP address")
+
+	request := vpc.CreateAllocateEipAddressRequest()
+	request.RegionId = s.scope.Region()
+	if spec.Bandwidth != 0 {
+		request.Bandwidth = strconv.FormatInt(spec.Bandwidth, 10)
+	}
+	request.InternetChargeType = spec.InternetChargeType
+	request.ResourceGroupId = scope.ResourceGroupID()
+
+	var response *vpc.AllocateEipAddressResponse
+	err := throttle.Do(s.scope.Region(), throttle.VPC, func() (err error) {
+		response, err = s.scope.VPC.AllocateEipAddress(request)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to allocate elastic IP address")
+	}
+
+	s.scope.V(2).Info("Allocated elastic IP address", "allocation-id", response.AllocationId, "ip", response.EipAddress)
+
+	// the address can not be tagged on allocation
+	if err := s.EnsureTags(ResourceTypeEIP, []string{response.AllocationId}, scope.Tags(), nil); err != nil {
+		return nil, err
+	}
+	return &infrav1.EIPStatus{
+		AllocationId: response.AllocationId,
+		IpAddress:    response.EipAddress,
+		Allocated:    true,
+	}, nil
+}
+
+// AssociateEIP associates the elastic IP address with the ECS instance.
+func (s *Service) AssociateEIP(allocationID, instanceID string) error {
+	s.scope.V(2).Info("Associating elastic IP address", "allocation-id", allocationID, "instance-id", instanceID)
+
+	request := vpc.CreateAssociateEipAddressRequest()
+	request.RegionId = s.scope.Region()
+	request.AllocationId = allocationID
+	request.InstanceId = instanceID
+	request.InstanceType = eipInstanceTypeECS
+
+	if err := throttle.Do(s.scope.Region(), throttle.VPC, func() error {
+		_, err := s.scope.VPC.AssociateEipAddress(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to associate elastic IP address %q with instance %q", allocationID, instanceID)
+	}
+	return nil
+}
+
+// ReleaseEIP releases the elastic IP address, it has to be unassociated first.
+func (s *Service) ReleaseEIP(allocationID string) error {
+	s.scope.V(2).Info("Releasing elastic IP address", "allocation-id", allocationID)
+
+	request := vpc.CreateReleaseEipAddressRequest()
+	request.RegionId = s.scope.Region()
+	request.AllocationId = allocationID
+
+	if err := throttle.Do(s.scope.Region(), throttle.VPC, func() error {
+		_, err := s.scope.VPC.ReleaseEipAddress(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to release elastic IP address %q", allocationID)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package vpc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/aliyunerrors"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// statusAvailable is the status of a vpc or vswitch which can be used
+	statusAvailable = "Available"
+)
+
+// CheckNetwork returns why the vpc and its vswitches can't be used yet, or nothing if they
+// are available and the vswitches belong to the vpc.
+func (s *Service) CheckNetwork(vpcID string, vswitchIDs []string) (string, error) {
+	vpcRequest := vpc.CreateDescribeVpcsRequest()
+	vpcRequest.RegionId = s.scope.Region()
+	vpcRequest.VpcId = vpcID
+
+	var vpcResponse *vpc.DescribeVpcsResponse
+	err := throttle.Do(s.scope.Region(), throttle.VPC, func() (err error) {
+		vpcResponse, err = s.scope.VPC.DescribeVpcs(vpcRequest)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe vpc %q", vpcID)
+	}
+	if len(vpcResponse.Vpcs.Vpc) == 0 {
+		return fmt.Sprintf("vpc %s not found", vpcID), nil
+	}
+	if status := vpcResponse.Vpcs.Vpc[0].Status; status != statusAvailable {
+		return fmt.Sprintf("vpc %s is %s", vpcID, status), nil
+	}
+	if len(vswitchIDs) == 0 {
+		return "", nil
+	}
+
+	vswitchRequest := vpc.CreateDescribeVSwitchesRequest()
+	vswitchRequest.RegionId = s.scope.Region()
+	vswitchRequest.VpcId = vpcID
+	vswitchRequest.VSwitchId = strings.Join(vswitchIDs, ",")
+	vswitchRequest.PageSize = requests.NewInteger(maxDescribePageSize)
+
+	var vswitchResponse *vpc.DescribeVSwitchesResponse
+	err = throttle.Do(s.scope.Region(), throttle.VPC, func() (err error) {
+		vswitchResponse, err = s.scope.VPC.DescribeVSwitches(vswitchRequest)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to describe vswitches %v of vpc %q", vswitchIDs, vpcID)
+	}
+	statuses := make(map[string]string, len(vswitchResponse.VSwitches.VSwitch))
+	for _, vswitch := range vswitchResponse.VSwitches.VSwitch {
+		statuses[vswitch.VSwitchId] = vswitch.Status
+	}
+	for _, id := range vswitchIDs {
+		status, ok := statuses[id]
+		if !ok {
+			return fmt.Sprintf("vswitch %s not found in vpc %s", id, vpcID), nil
+		}
+		if status != statusAvailable {
+			return fmt.Sprintf("vswitch %s is %s", id, status), nil
+		}
+	}
+	return "", nil
+}
+
+// DeleteNatGateway deletes the NAT gateway together with its SNAT and DNAT entries. The
+// deletion is asynchronous, deleting a NAT gateway which is being deleted already succeeds.
+func (s *Service) DeleteNatGateway(id string) error {
+	s.scope.V(2).Info("Deleting NAT gateway", "nat-gateway-id", id)
+
+	request := vpc.CreateDeleteNatGatewayRequest()
+	request.RegionId = s.scope.Region()
+	request.NatGatewayId = id
+	request.Force = requests.NewBoolean(true)
+
+	if err := throttle.Do(s.scope.Region(), throttle.VPC, func() error {
+		_, err := s.scope.VPC.DeleteNatGateway(request)
+		return err
+	}); err != nil {
+		if aliyunerrors.IsNotFound(err) || aliyunerrors.IsIncorrectInstanceStatus(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete NAT gateway %q", id)
+	}
+	return nil
+}
+
+// DeleteVSwitch deletes the vswitch.
+func (s *Service) DeleteVSwitch(id string) error {
+	s.scope.V(2).Info("Deleting vswitch", "vswitch-id", id)
+
+	request := vpc.CreateDeleteVSwitchRequest()
+	request.RegionId = s.scope.Region()
+	request.VSwitchId = id
+
+	if err := throttle.Do(s.scope.Region(), throttle.VPC, func() error {
+		_, err := s.scope.VPC.DeleteVSwitch(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to delete vswitch %q", id)
+	}
+	return nil
+}
+
+// DeleteVPC deletes the vpc.
+func (s *Service) DeleteVPC(id string) error {
+	s.scope.V(2).Info("Deleting vpc", "vpc-id", id)
+
+	request := vpc.CreateDeleteVpcRequest()
+	request.RegionId = s.scope.Region()
+	request.VpcId = id
+
+	if err := throttle.Do(s.scope.Region(), throttle.VPC, func() error {
+		_, err := s.scope.VPC.DeleteVpc(request)
+		return err
+	}); err != nil {
+		return errors.Wrapf(err, "failed to delete vpc %q", id)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package vpc
+
+import (
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/scope"
+)
+
+// Service holds a collection of interfaces.
+// The interfaces are broken down like this to group functions together.
+// One alternative is to have a large list of functions from the vpc client.
+type Service struct {
+	scope *scope.ClusterScope
+}
+
+// NewService returns a new service given the vpc api client.
+func NewService(clusterScope *scope.ClusterScope) *Service {
+	return &Service{
+		scope: clusterScope,
+	}
+}
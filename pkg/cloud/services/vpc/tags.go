@@ -0,0 +1,142 @@
+package vpc
+
+import (
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ResourceTypeVPC is the tag resource type of vpcs
+	ResourceTypeVPC = "VPC"
+	// ResourceTypeVSwitch is the tag resource type of vswitches
+	ResourceTypeVSwitch = "VSWITCH"
+	// ResourceTypeEIP is the tag resource type of elastic IP addresses
+	ResourceTypeEIP = "EIP"
+	// ResourceTypeNATGateway is the tag resource type of NAT gateways
+	ResourceTypeNATGateway = "NATGATEWAY"
+)
+
+// EnsureTags applies the tags which are missing or have drifted to the resources, and
+// removes the given tag keys from them unless they are part of the tags.
+func (s *Service) EnsureTags(resourceType string, ids []string, tags infrav1.Tags, removed []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	current, err := s.listTags(resourceType, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if diff := tags.Difference(current[id]); len(diff) > 0 {
+			s.scope.V(2).Info("Tagging resource", "resource-type", resourceType, "resource-id", id, "tags", diff)
+			request := vpc.CreateTagResourcesRequest()
+			request.RegionId = s.scope.Region()
+			request.ResourceType = resourceType
+			request.ResourceId = &[]string{id}
+			var sdkTags []vpc.TagResourcesTag
+			for _, key := range diff.Keys() {
+				sdkTags = append(sdkTags, vpc.TagResourcesTag{Key: key, Value: diff[key]})
+			}
+			request.Tag = &sdkTags
+			if err := throttle.Do(s.scope.Region(), throttle.VPC, func() error {
+				_, err := s.scope.VPC.TagResources(request)
+				return err
+			}); err != nil {
+				return errors.Wrapf(err, "failed to tag %s %q", resourceType, id)
+			}
+		}
+
+		var stale []string
+		for _, key := range removed {
+			_, set := current[id][key]
+			_, wanted := tags[key]
+			if set && !wanted {
+				stale = append(stale, key)
+			}
+		}
+		if len(stale) > 0 {
+			s.scope.V(2).Info("Untagging resource", "resource-type", resourceType, "resource-id", id, "keys", stale)
+			request := vpc.CreateUnTagResourcesRequest()
+			request.RegionId = s.scope.Region()
+			request.ResourceType = resourceType
+			request.ResourceId = &[]string{id}
+			request.TagKey = &stale
+			if err := throttle.Do(s.scope.Region(), throttle.VPC, func() error {
+				_, err := s.scope.VPC.UnTagResources(request)
+				return err
+			}); err != nil {
+				return errors.Wrapf(err, "failed to untag %s %q", resourceType, id)
+			}
+		}
+	}
+	return nil
+}
+
+// TaggedResources returns the IDs of the resources of the type which carry all the tags.
+func (s *Service) TaggedResources(resourceType string, tags infrav1.Tags) ([]string, error) {
+	request := vpc.CreateListTagResourcesRequest()
+	request.RegionId = s.scope.Region()
+	request.ResourceType = resourceType
+	var sdkTags []vpc.ListTagResourcesTag
+	for _, key := range tags.Keys() {
+		sdkTags = append(sdkTags, vpc.ListTagResourcesTag{Key: key, Value: tags[key]})
+	}
+	request.Tag = &sdkTags
+
+	var ids []string
+	seen := map[string]bool{}
+	for {
+		var response *vpc.ListTagResourcesResponse
+		err := throttle.Do(s.scope.Region(), throttle.VPC, func() (err error) {
+			response, err = s.scope.VPC.ListTagResources(request)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list tagged %s", resourceType)
+		}
+		for _, resource := range response.TagResources.TagResource {
+			if !seen[resource.ResourcId] {
+				seen[resource.ResourcId] = true
+				ids = append(ids, resource.ResourcId)
+			}
+		}
+		if response.NextToken == "" {
+			return ids, nil
+		}
+		request.NextToken = response.NextToken
+	}
+}
+
+// listTags returns the tags of the resources by resource ID.
+func (s *Service) listTags(resourceType string, ids []string) (map[string]infrav1.Tags, error) {
+	request := vpc.CreateListTagResourcesRequest()
+	request.RegionId = s.scope.Region()
+	request.ResourceType = resourceType
+	request.ResourceId = &ids
+
+	tags := map[string]infrav1.Tags{}
+	for {
+		var response *vpc.ListTagResourcesResponse
+		err := throttle.Do(s.scope.Region(), throttle.VPC, func() (err error) {
+			response, err = s.scope.VPC.ListTagResources(request)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list tags of %s %v", resourceType, ids)
+		}
+		for _, resource := range response.TagResources.TagResource {
+			if tags[resource.ResourcId] == nil {
+				tags[resource.ResourcId] = infrav1.Tags{}
+			}
+			tags[resource.ResourcId][resource.TagKey] = resource.TagValue
+		}
+		if response.NextToken == "" {
+			return tags, nil
+		}
+		request.NextToken = response.NextToken
+	}
+}
@@ -0,0 +1,57 @@
+package vpc
+
+import (
+	"strings"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/throttle"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxDescribePageSize is the largest page size accepted by the describe apis
+	maxDescribePageSize = 50
+)
+
+// DescribeVSwitches returns the vswitches with the given IDs in the order of the IDs.
+func (s *Service) DescribeVSwitches(ids []string) ([]infrav1.VSwitch, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	request := vpc.CreateDescribeVSwitchesRequest()
+	request.RegionId = s.scope.Region()
+	request.VSwitchId = strings.Join(ids, ",")
+	request.PageSize = requests.NewInteger(maxDescribePageSize)
+
+	var response *vpc.DescribeVSwitchesResponse
+	err := throttle.Do(s.scope.Region(), throttle.VPC, func() (err error) {
+		response, err = s.scope.VPC.DescribeVSwitches(request)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe vswitches %v", ids)
+	}
+
+	byID := make(map[string]vpc.VSwitch, len(response.VSwitches.VSwitch))
+	for _, vswitch := range response.VSwitches.VSwitch {
+		byID[vswitch.VSwitchId] = vswitch
+	}
+
+	vswitches := make([]infrav1.VSwitch, 0, len(ids))
+	for _, id := range ids {
+		vswitch, ok := byID[id]
+		if !ok {
+			return nil, errors.Errorf("vswitch %q not found", id)
+		}
+		vswitches = append(vswitches, infrav1.VSwitch{
+			ID:                      vswitch.VSwitchId,
+			ZoneId:                  vswitch.ZoneId,
+			CidrBlock:               vswitch.CidrBlock,
+			AvailableIpAddressCount: vswitch.AvailableIpAddressCount,
+		})
+	}
+	return vswitches, nil
+}
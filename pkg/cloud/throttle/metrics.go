@@ -0,0 +1,46 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// waitReasonRateLimit is the wait for a token of the bucket
+	waitReasonRateLimit = "ratelimit"
+	// waitReasonBackoff is the wait before retrying a throttled call
+	waitReasonBackoff = "backoff"
+)
+
+var (
+	throttledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ack_api_throttled_total",
+		Help: "Total number of aliyun api calls which have been throttled by the api.",
+	}, []string{"region", "group"})
+
+	waitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ack_api_wait_seconds",
+		Help:    "Time aliyun api calls waited for the client-side rate limiter or before being retried.",
+		Buckets: []float64{0.001, 0.01, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"region", "group", "reason"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(throttledTotal, waitSeconds)
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package throttle rate limits the calls of the aliyun api on the client side and retries
+// the calls which have been throttled nevertheless. The aliyun api throttles per region and
+// api group, so does the client.
+package throttle
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IrisIris/cluster-api-provider-aliyun/pkg/cloud/aliyunerrors"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// APIGroup is a group of apis sharing a rate limit, e.g. all ecs apis.
+type APIGroup string
+
+const (
+	// ECS is the api group of the ecs apis.
+	ECS = APIGroup("ecs")
+	// VPC is the api group of the vpc apis.
+	VPC = APIGroup("vpc")
+	// SLB is the api group of the slb apis.
+	SLB = APIGroup("slb")
+	// ResourceManager is the api group of the resource manager apis.
+	ResourceManager = APIGroup("resourcemanager")
+)
+
+// apiGroups are the api groups the limits can be configured for.
+var apiGroups = []APIGroup{ECS, VPC, SLB, ResourceManager}
+
+// Limit is the token bucket of an api group in a region.
+type Limit struct {
+	// QPS is the rate the bucket is refilled with.
+	QPS float64
+	// Burst is the size of the bucket.
+	Burst int
+}
+
+// Options configure the rate limits and the retries of throttled calls.
+type Options struct {
+	Limits map[APIGroup]Limit
+	// MaxRetries is how often a throttled call is retried.
+	MaxRetries int
+	// BaseDelay is the maximum delay of the first retry, it doubles with every retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the maximum delay of the retries.
+	MaxDelay time.Duration
+}
+
+// DefaultOptions returns the default limits, they stay well below the default flow control
+// of the aliyun apis.
+func DefaultOptions() Options {
+	return Options{
+		Limits: map[APIGroup]Limit{
+			ECS:             {QPS: 10, Burst: 20},
+			VPC:             {QPS: 10, Burst: 20},
+			SLB:             {QPS: 10, Burst: 20},
+			ResourceManager: {QPS: 5, Burst: 10},
+		},
+		MaxRetries: 5,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   30 * time.Second,
+	}
+}
+
+var (
+	mu       sync.Mutex
+	options  = DefaultOptions()
+	limiters = map[string]*rate.Limiter{}
+)
+
+// Configure replaces the options, the limits of the api groups which are not configured
+// keep their defaults. It is meant to be called once on startup.
+func Configure(o Options) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	limits := DefaultOptions().Limits
+	for group, limit := range o.Limits {
+		limits[group] = limit
+	}
+	o.Limits = limits
+	options = o
+	limiters = map[string]*rate.Limiter{}
+}
+
+// ParseLimits parses limits in the form ecs=10:20,vpc=5:10, i.e. the QPS and the burst by api group.
+func ParseLimits(s string) (map[APIGroup]Limit, error) {
+	limits := map[APIGroup]Limit{}
+	if s == "" {
+		return limits, nil
+	}
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid rate limit %q, expected <group>=<qps>:<burst>", entry)
+		}
+		values := strings.SplitN(parts[1], ":", 2)
+		if len(values) != 2 {
+			return nil, errors.Errorf("invalid rate limit %q, expected <group>=<qps>:<burst>", entry)
+		}
+		qps, err := strconv.ParseFloat(values[0], 64)
+		if err != nil || qps <= 0 {
+			return nil, errors.Errorf("invalid QPS in rate limit %q", entry)
+		}
+		burst, err := strconv.Atoi(values[1])
+		if err != nil || burst <= 0 {
+			return nil, errors.Errorf("invalid burst in rate limit %q", entry)
+		}
+		group := APIGroup(strings.TrimSpace(parts[0]))
+		if !isAPIGroup(group) {
+			return nil, errors.Errorf("unknown api group %q in rate limit %q, expected one of %v", group, entry, apiGroups)
+		}
+		limits[group] = Limit{QPS: qps, Burst: burst}
+	}
+	return limits, nil
+}
+
+// isAPIGroup returns true if the limits of the api group can be configured.
+func isAPIGroup(group APIGroup) bool {
+	for _, g := range apiGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// limiter returns the shared token bucket of the api group in the region.
+func limiter(region string, group APIGroup) (*rate.Limiter, Options) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	key := region + "/" + string(group)
+	l, ok := limiters[key]
+	if !ok {
+		limit, ok := options.Limits[group]
+		if !ok {
+			limit = Limit{QPS: float64(rate.Inf), Burst: 1}
+		}
+		l = rate.NewLimiter(rate.Limit(limit.QPS), limit.Burst)
+		limiters[key] = l
+	}
+	return l, options
+}
+
+// Do calls the api of the group in the region once the token bucket of the region and group
+// allows it. Throttled calls are retried with exponential backoff and full jitter, any other
+// error is returned as is.
+func Do(region string, group APIGroup, call func() error) error {
+	l, o := limiter(region, group)
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		if err := l.Wait(context.TODO()); err != nil {
+			return errors.Wrapf(err, "failed to wait for the %s rate limiter of region %q", group, region)
+		}
+		waitSeconds.WithLabelValues(region, string(group), waitReasonRateLimit).Observe(time.Since(start).Seconds())
+
+		err := call()
+		if err == nil || !aliyunerrors.IsThrottling(err) {
+			return err
+		}
+		throttledTotal.WithLabelValues(region, string(group)).Inc()
+		if attempt >= o.MaxRetries {
+			return err
+		}
+
+		delay := backoff(o, attempt)
+		waitSeconds.WithLabelValues(region, string(group), waitReasonBackoff).Observe(delay.Seconds())
+		time.Sleep(delay)
+	}
+}
+
+// backoff returns a random delay up to the exponentially growing maximum delay of the attempt.
+func backoff(o Options, attempt int) time.Duration {
+	delay := o.MaxDelay
+	if attempt < 32 {
+		if d := o.BaseDelay << uint(attempt); d > 0 && d < delay {
+			delay = d
+		}
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay))) + 1
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package throttle
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+)
+
+func serverError(code string) error {
+	return sdkerrors.NewServerError(400, fmt.Sprintf(`{"Code":%q,"Message":"message","RequestId":"request-id"}`, code), "")
+}
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  string
+		want    map[APIGroup]Limit
+		wantErr bool
+	}{
+		{name: "empty", limits: "", want: map[APIGroup]Limit{}},
+		{name: "single group", limits: "ecs=10:20", want: map[APIGroup]Limit{ECS: {QPS: 10, Burst: 20}}},
+		{name: "all groups", limits: "ecs=10:20, vpc=5:10,slb=2.5:5,resourcemanager=1:1", want: map[APIGroup]Limit{
+			ECS:             {QPS: 10, Burst: 20},
+			VPC:             {QPS: 5, Burst: 10},
+			SLB:             {QPS: 2.5, Burst: 5},
+			ResourceManager: {QPS: 1, Burst: 1},
+		}},
+		{name: "unknown group", limits: "rds=10:20", wantErr: true},
+		{name: "misspelled group", limits: "ECS=10:20", wantErr: true},
+		{name: "missing burst", limits: "ecs=10", wantErr: true},
+		{name: "missing limit", limits: "ecs", wantErr: true},
+		{name: "invalid qps", limits: "ecs=fast:20", wantErr: true},
+		{name: "zero qps", limits: "ecs=0:20", wantErr: true},
+		{name: "negative burst", limits: "ecs=10:-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			limits, err := ParseLimits(tt.limits)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(limits).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	Configure(Options{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	defer Configure(DefaultOptions())
+
+	tests := []struct {
+		name  string
+		errs  []error
+		calls int
+		err   bool
+	}{
+		{name: "success", errs: []error{nil}, calls: 1},
+		{name: "retried after throttling", errs: []error{serverError("Throttling.User"), serverError("Throttling"), nil}, calls: 3},
+		{name: "retries exhausted", errs: []error{serverError("Throttling"), serverError("Throttling"), serverError("Throttling"), nil}, calls: 3, err: true},
+		{name: "other error not retried", errs: []error{serverError("InvalidParameter"), nil}, calls: 1, err: true},
+		{name: "other error after throttling", errs: []error{serverError("Throttling"), serverError("ServiceUnavailable"), nil}, calls: 2, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			calls := 0
+			err := Do("cn-hangzhou", ECS, func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			g.Expect(calls).To(Equal(tt.calls))
+			if tt.err {
+				g.Expect(err).To(Equal(tt.errs[calls-1]))
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	o := Options{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		name    string
+		attempt int
+		max     time.Duration
+	}{
+		{name: "first retry", attempt: 0, max: 100 * time.Millisecond},
+		{name: "doubled", attempt: 2, max: 400 * time.Millisecond},
+		{name: "capped", attempt: 4, max: time.Second},
+		{name: "overflowing shift", attempt: 40, max: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			for i := 0; i < 100; i++ {
+				delay := backoff(o, tt.attempt)
+				g.Expect(delay).To(BeNumerically(">", 0))
+				g.Expect(delay).To(BeNumerically("<=", tt.max))
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 ALIYUN.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conditions implements helpers to manage the conditions of the ACK resources.
+package conditions
+
+import (
+	"fmt"
+
+	infrav1 "github.com/IrisIris/cluster-api-provider-aliyun/api/v1alpha4"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Setter interface defines methods that an ACK object should implement in order to
+// use the conditions package for getting and setting conditions.
+type Setter interface {
+	GetConditions() infrav1.Conditions
+	SetConditions(infrav1.Conditions)
+}
+
+// Get returns the condition with the given type, if the condition does not exist, it returns nil.
+func Get(from Setter, t infrav1.ConditionType) *infrav1.Condition {
+	for _, condition := range from.GetConditions() {
+		if condition.Type == t {
+			c := condition
+			return &c
+		}
+	}
+	return nil
+}
+
+// IsTrue is true if the condition with the given type is True, otherwise it returns false.
+func IsTrue(from Setter, t infrav1.ConditionType) bool {
+	if c := Get(from, t); c != nil {
+		return c.Status == corev1.ConditionTrue
+	}
+	return false
+}
+
+// IsFalse is true if the condition with the given type is False, otherwise it returns false.
+func IsFalse(from Setter, t infrav1.ConditionType) bool {
+	if c := Get(from, t); c != nil {
+		return c.Status == corev1.ConditionFalse
+	}
+	return false
+}
+
+// GetReason returns the reason of the condition with the given type, if the condition does not exist,
+// it returns an empty reason.
+func GetReason(from Setter, t infrav1.ConditionType) string {
+	if c := Get(from, t); c != nil {
+		return c.Reason
+	}
+	return ""
+}
+
+// Set sets the given condition.
+// If a condition with the same type already exists, the LastTransitionTime is only
+// updated when the status changes.
+func Set(to Setter, condition *infrav1.Condition) {
+	if to == nil || condition == nil {
+		return
+	}
+
+	conditions := to.GetConditions()
+	for i := range conditions {
+		existing := conditions[i]
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		} else {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		conditions[i] = *condition
+		to.SetConditions(conditions)
+		return
+	}
+
+	condition.LastTransitionTime = metav1.Now()
+	to.SetConditions(append(conditions, *condition))
+}
+
+// MarkTrue sets Status=True for the condition with the given type.
+func MarkTrue(to Setter, t infrav1.ConditionType) {
+	Set(to, &infrav1.Condition{
+		Type:   t,
+		Status: corev1.ConditionTrue,
+	})
+}
+
+// MarkFalse sets Status=False for the condition with the given type.
+func MarkFalse(to Setter, t infrav1.ConditionType, reason string, messageFormat string, messageArgs ...interface{}) {
+	Set(to, &infrav1.Condition{
+		Type:    t,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: fmt.Sprintf(messageFormat, messageArgs...),
+	})
+}
+
+// Delete deletes the condition with the given type.
+func Delete(to Setter, t infrav1.ConditionType) {
+	if to == nil {
+		return
+	}
+
+	conditions := to.GetConditions()
+	newConditions := make(infrav1.Conditions, 0, len(conditions))
+	for _, condition := range conditions {
+		if condition.Type != t {
+			newConditions = append(newConditions, condition)
+		}
+	}
+	to.SetConditions(newConditions)
+}